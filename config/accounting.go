@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Accounting configures the persistent traffic totals of the network
+// interfaces and the quotas checked against them.
+type Accounting struct {
+	// Path is the JSON file the totals are kept in.
+	Path   string  `json:"path,omitempty"`
+	Quotas []Quota `json:"quotas,omitempty"`
+}
+
+// Quota limits the traffic of a network, by the name it is shown as, per
+// "day" or "month". Limit is a size like "20GiB" or "500MB". Warn is the
+// percentage of the limit to warn at, 80 by default.
+type Quota struct {
+	Network string  `json:"network"`
+	Period  string  `json:"period"`
+	Limit   string  `json:"limit"`
+	Warn    float64 `json:"warn,omitempty"`
+}
+
+func DefaultAccountingPath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".local", "state")
+	}
+	return filepath.Join(dir, "harvey-gl", "traffic.json")
+}
+
+func (a *Accounting) fill(def Accounting) {
+	if a.Path == "" {
+		a.Path = def.Path
+	}
+}
+
+var byteUnits = []struct {
+	suffix string
+	factor float64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"T", 1 << 40},
+	{"B", 1},
+}
+
+// ParseBytes parses a size like "20GiB", "1.5 GB" or "4096". The short
+// suffixes K, M, G and T are binary.
+func ParseBytes(size string) (float64, error) {
+	s := strings.TrimSpace(size)
+	factor := 1.0
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			factor = unit.factor
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	return value * factor, nil
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Config is read from a JSON file, every section falls back to its default
+// when left out.
+type Config struct {
+	Status     Status      `json:"status"`
+	Network    Network     `json:"network"`
+	Accounting Accounting  `json:"accounting"`
+	Disk       Disk        `json:"disk"`
+	Load       LoadAverage `json:"load"`
+	FanControl FanControl  `json:"fan_control"`
+}
+
+// LoadAverage configures the load averages, PerCore divides them by the
+// number of cores so that 1 means fully loaded on any machine.
+type LoadAverage struct {
+	PerCore bool `json:"per_core,omitempty"`
+}
+
+type Status struct {
+	Segments []Segment `json:"segments"`
+}
+
+// Segment configures one part of the status bar. Type is one of clock,
+// memory, fan, fans, fancontrol, thermal, cpu, load, uptime, pressure,
+// network, traffic, power, battery or custom.
+type Segment struct {
+	Type       string  `json:"type"`
+	Name       string  `json:"name,omitempty"`
+	Align      string  `json:"align,omitempty"`
+	Foreground string  `json:"foreground,omitempty"`
+	Background string  `json:"background,omitempty"`
+	MinWidth   int     `json:"min_width,omitempty"`
+	Separator  *string `json:"separator,omitempty"`
+	Hidden     bool    `json:"hidden,omitempty"`
+
+	// Inline overrides the segment's default inline graphic with "spark",
+	// "bar" or "none".
+	Inline string `json:"inline,omitempty"`
+
+	// Format replaces the segment's default text/template, see status.Data
+	// for the fields and status.Funcs for the helpers available.
+	Format string `json:"format,omitempty"`
+
+	// Text is the fixed text of custom segments without a Format.
+	Text string `json:"text,omitempty"`
+}
+
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(dir, "harvey-gl", "config.json")
+}
+
+func Default() *Config {
+	return &Config{
+		Status: Status{
+			Segments: []Segment{
+				{Type: "clock", Align: "left"},
+				{Type: "memory", Align: "right"},
+				{Type: "fan", Align: "right"},
+				{Type: "thermal", Align: "right"},
+				{Type: "cpu", Align: "right"},
+				{Type: "network", Align: "right"},
+				{Type: "battery", Align: "right"},
+			},
+		},
+		Network:    DefaultNetwork(),
+		Accounting: Accounting{Path: DefaultAccountingPath()},
+		Disk:       DefaultDisk(),
+		FanControl: DefaultFanControl(),
+	}
+}
+
+// Load reads the config at path. A missing file is not an error, the
+// defaults are returned instead.
+func Load(path string) (*Config, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Default(), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	if err := json.Unmarshal(buf, c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	c.fill(Default())
+	return c, nil
+}
+
+// fill copies the sections left out of the file from def.
+func (c *Config) fill(def *Config) {
+	if c.Status.Segments == nil {
+		c.Status.Segments = def.Status.Segments
+	}
+	c.Network.fill(def.Network)
+	c.Accounting.fill(def.Accounting)
+	c.Disk.fill(def.Disk)
+	c.FanControl.fill(def.FanControl)
+}
+
+// ParseColor parses "#rrggbb" or "#rrggbbaa". An empty string yields def.
+func ParseColor(s string, def color.RGBA) (color.RGBA, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	c := color.RGBA{A: 0xff}
+	var err error
+	switch len(s) {
+	case 7:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 9:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		err = fmt.Errorf("expected #rrggbb")
+	}
+	if err != nil {
+		return def, fmt.Errorf("invalid color %q: %v", s, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,31 @@
+package config
+
+// Disk selects the block devices whose I/O is collected, by glob patterns as
+// understood by path.Match, and the mount points whose usage is collected.
+type Disk struct {
+	Devices []string `json:"devices,omitempty"`
+	Mounts  []string `json:"mounts,omitempty"`
+}
+
+// DefaultDisk selects whole disks, not their partitions, and the root
+// filesystem.
+func DefaultDisk() Disk {
+	return Disk{
+		Devices: []string{"sd?", "vd?", "xvd?", "nvme?n?", "mmcblk?"},
+		Mounts:  []string{"/"},
+	}
+}
+
+// Selects tells whether I/O of the device is collected.
+func (d Disk) Selects(device string) bool {
+	return matchAny(d.Devices, device)
+}
+
+func (d *Disk) fill(def Disk) {
+	if d.Devices == nil {
+		d.Devices = def.Devices
+	}
+	if d.Mounts == nil {
+		d.Mounts = def.Mounts
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+// FanControl configures the optional fan curve. The fan is driven through
+// Device, "thinkpad" for /proc/acpi/ibm/fan, which needs thinkpad_acpi loaded
+// with fan_control=1, or "pwm" for the hwmon pwm file at PWM, the first fan
+// with one when left out. An empty Device picks thinkpad where available.
+// thinkpad_acpi takes the fan back by itself when harvey stops updating it.
+//
+// The curve maps temperatures in °C to levels 0 to 7, and 8 for full speed;
+// the fan runs at the level of the highest point at or below the temperature.
+// The levels may not decrease as the temperature rises. The fan goes up after
+// UpDwell and down after DownDwell at a level, and down only once the
+// temperature dropped Hysteresis °C below the point; Hysteresis is a pointer
+// so that an explicit 0 is told apart from leaving it out.
+type FanControl struct {
+	Enabled bool   `json:"enabled"`
+	Device  string `json:"device,omitempty"`
+	PWM     string `json:"pwm,omitempty"`
+
+	Curve      []CurvePoint `json:"curve,omitempty"`
+	Hysteresis *float64     `json:"hysteresis,omitempty"`
+	UpDwell    string       `json:"up_dwell,omitempty"`
+	DownDwell  string       `json:"down_dwell,omitempty"`
+}
+
+type CurvePoint struct {
+	Temp  float64 `json:"temp"`
+	Level int     `json:"level"`
+}
+
+func DefaultFanControl() FanControl {
+	hysteresis := 3.0
+	return FanControl{
+		Curve: []CurvePoint{
+			{Temp: 0, Level: 0},
+			{Temp: 50, Level: 1},
+			{Temp: 60, Level: 3},
+			{Temp: 70, Level: 5},
+			{Temp: 80, Level: 7},
+			{Temp: 90, Level: 8},
+		},
+		Hysteresis: &hysteresis,
+		UpDwell:    "5s",
+		DownDwell:  "30s",
+	}
+}
+
+func (f *FanControl) fill(def FanControl) {
+	if f.Curve == nil {
+		f.Curve = def.Curve
+	}
+	if f.Hysteresis == nil {
+		f.Hysteresis = def.Hysteresis
+	}
+	if f.UpDwell == "" {
+		f.UpDwell = def.UpDwell
+	}
+	if f.DownDwell == "" {
+		f.DownDwell = def.DownDwell
+	}
+}
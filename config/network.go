@@ -0,0 +1,105 @@
+package config
+
+import (
+	"path"
+	"sort"
+)
+
+// Network selects the interfaces shown and how they are named. Include,
+// Exclude, Groups and Order take glob patterns as understood by path.Match,
+// e.g. "veth*".
+type Network struct {
+	// Include limits the interfaces to those matching a pattern, all when
+	// empty. Exclude then drops those matching any of its patterns.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Aliases renames interfaces, e.g. "wlp3s0": "wifi".
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// Groups sum several interfaces into one, e.g. a "vpn" of tun* and wg*.
+	Groups []NetworkGroup `json:"groups,omitempty"`
+
+	// Order lists the names to show first, after aliasing and grouping; the
+	// rest follow sorted by name.
+	Order []string `json:"order,omitempty"`
+}
+
+type NetworkGroup struct {
+	Name       string   `json:"name"`
+	Interfaces []string `json:"interfaces"`
+}
+
+func DefaultNetwork() Network {
+	return Network{
+		Exclude: []string{"lo", "docker*", "veth*", "virbr*", "br-*"},
+		Aliases: map[string]string{
+			"enp0s25": "lan",
+			"wlp3s0":  "wifi",
+		},
+	}
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Selects tells whether the interface is shown.
+func (n Network) Selects(iface string) bool {
+	if len(n.Include) > 0 && !matchAny(n.Include, iface) {
+		return false
+	}
+	return !matchAny(n.Exclude, iface)
+}
+
+// Name returns the name the interface is shown as: the group it belongs to,
+// its alias, or its own name.
+func (n Network) Name(iface string) string {
+	for _, group := range n.Groups {
+		if matchAny(group.Interfaces, iface) {
+			return group.Name
+		}
+	}
+	if alias, ok := n.Aliases[iface]; ok {
+		return alias
+	}
+	return iface
+}
+
+// rank is the position of the first Order pattern matching name, or
+// len(Order) when none does.
+func (n Network) rank(name string) int {
+	for i, pattern := range n.Order {
+		if ok, _ := path.Match(pattern, name); ok {
+			return i
+		}
+	}
+	return len(n.Order)
+}
+
+// Sort sorts names by Order, then by name.
+func (n Network) Sort(names []string) {
+	sort.SliceStable(names, func(i, j int) bool {
+		ri, rj := n.rank(names[i]), n.rank(names[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+}
+
+// fill copies the settings left out of the file from def. An empty list in
+// the file is kept, so "exclude": [] shows every interface.
+func (n *Network) fill(def Network) {
+	if n.Exclude == nil {
+		n.Exclude = def.Exclude
+	}
+	if n.Aliases == nil {
+		n.Aliases = def.Aliases
+	}
+}
@@ -1,18 +1,32 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"image/color"
 	"log"
+	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
 	"time"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 
+	"github.com/maurodelazeri/harvey-gl/config"
 	"github.com/maurodelazeri/harvey-gl/shader"
 	"github.com/maurodelazeri/harvey-gl/widgets"
+	"github.com/maurodelazeri/harvey-gl/widgets/battery"
+	"github.com/maurodelazeri/harvey-gl/widgets/disk"
+	"github.com/maurodelazeri/harvey-gl/widgets/gauge"
+	"github.com/maurodelazeri/harvey-gl/widgets/graph"
+	"github.com/maurodelazeri/harvey-gl/widgets/heatmap"
+	"github.com/maurodelazeri/harvey-gl/widgets/memory"
+	"github.com/maurodelazeri/harvey-gl/widgets/network"
+	"github.com/maurodelazeri/harvey-gl/widgets/pressure"
+	"github.com/maurodelazeri/harvey-gl/widgets/process"
 	"github.com/maurodelazeri/harvey-gl/widgets/status"
-	"github.com/maurodelazeri/harvey-gl/widgets/thermal"
 )
 
 func init() {
@@ -34,6 +48,31 @@ func keyCallback(window *glfw.Window, key glfw.Key, scancode int, action glfw.Ac
 	if key == glfw.KeyEscape && action == glfw.Press {
 		window.SetShouldClose(true)
 	}
+	if processTable != nil && action == glfw.Press {
+		switch key {
+		case glfw.KeyC:
+			processTable.SortBy(process.SortCPU)
+		case glfw.KeyM:
+			processTable.SortBy(process.SortRSS)
+		case glfw.KeyI:
+			processTable.SortBy(process.SortIO)
+		case glfw.KeyTab:
+			processTable.Next()
+		}
+		processTable.Stats.Lock()
+		processTable.Render()
+		processTable.Stats.Unlock()
+	}
+	if fanControl != nil && action == glfw.Press {
+		switch key {
+		case glfw.KeyUp:
+			fanControl.Step(1)
+		case glfw.KeyDown:
+			fanControl.Step(-1)
+		case glfw.KeyF:
+			fanControl.Auto()
+		}
+	}
 	triggerRedraw()
 }
 
@@ -59,7 +98,54 @@ var WindowHeight int = 600
 
 var program *shader.Program
 
+// processTable is sorted from the keyboard, fanControl overridden, see
+// keyCallback.
+var processTable *process.Table
+var fanControl *widgets.FanController
+
+// releaseFan hands the fan back to the firmware, however harvey exits, so that
+// it isn't left stuck at a slow level.
+func releaseFan() {
+	if fanControl == nil {
+		return
+	}
+	if err := fanControl.Release(); err != nil {
+		log.Println("failed to hand the fan back to the firmware:", err)
+	}
+}
+
+// releaseFanOnPanic is deferred by every goroutine that runs while the fan
+// is controlled.
+func releaseFanOnPanic() {
+	if r := recover(); r != nil {
+		releaseFan()
+		panic(r)
+	}
+}
+
+// guarded wraps a goroutine in releaseFanOnPanic.
+func guarded(run func()) func() {
+	return func() {
+		defer releaseFanOnPanic()
+		run()
+	}
+}
+
+func fatal(v ...interface{}) {
+	releaseFan()
+	log.Fatalln(v...)
+}
+
+var configPath = flag.String("config", config.DefaultPath(), "path to the JSON config file")
+
 func main() {
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalln("failed to load config:", err)
+	}
+
 	if err := glfw.Init(); err != nil {
 		log.Fatalln("failed to initialize glfw:", err)
 	}
@@ -116,12 +202,65 @@ func main() {
 	*/
 
 	stats := widgets.NewStats()
-	go stats.Run()
+	stats.Disk = cfg.Disk
+	stats.System.PerCore = cfg.Load.PerCore
+	defer releaseFanOnPanic()
+	if cfg.FanControl.Enabled {
+		if fanControl, err = widgets.NewFanController(cfg.FanControl); err != nil {
+			fatal("failed to set up fan control:", err)
+		}
+		stats.FanControl = fanControl
+	}
+
+	// Interrupting or terminating harvey closes the window, so that it exits
+	// the same way as when closed.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
-	status := status.New(WindowWidth, WindowHeight, program, stats)
-	go status.Run()
+	go guarded(stats.Run)()
 
-	graphs := thermal.New(program, stats)
+	status, err := status.New(WindowWidth, WindowHeight, program, stats, cfg)
+	if err != nil {
+		fatal("failed to set up status bar:", err)
+	}
+	go guarded(status.Run)()
+
+	graphs := graph.NewPanel(program, 20, float64(WindowHeight)-status.Texture.Height-20, 300,
+		graph.New(stats.Thermal, "%.0fC", 300, 60).Scaled(graph.ScaleNice).Annotated().
+			Overlay(stats.Fan, "%.0f RPM", color.RGBA{0x99, 0x66, 0x33, 0xff}, true).Marked(stats.Events),
+		graph.New(stats.Fan, "%.0f RPM", 300, 40).Fixed(0, 10000),
+		graph.New(stats.Cpu, "%.0f%% CPU", 300, 40).Fixed(0, 100).Styled(graph.StyleBars).Marked(stats.Events),
+		graph.New(stats.Memory, "%.0f%% RAM", 300, 40).Fixed(0, 100).Styled(graph.StyleArea),
+		graph.New(stats.System.Load1, "%.2f load", 300, 40).Scaled(graph.ScaleNice).Styled(graph.StyleLine),
+	)
+
+	cores := heatmap.New(program, 340, graphs.Texture.Y, 300, 120, stats.Cores).Fixed(0, 100)
+	sensors := heatmap.New(program, 1040, graphs.Texture.Y, 300, 60, stats.Sensors)
+	traffic := heatmap.New(program, 1040, graphs.Texture.Y-70, 300, 60, nil)
+	traffic.Ramp = heatmap.RampTraffic
+
+	red := color.RGBA{0xcc, 0x33, 0x33, 0xff}
+	yellow := color.RGBA{0xcc, 0xaa, 0x33, 0xff}
+
+	batteryDial := gauge.NewDial(program, 660, graphs.Texture.Y, 100, stats.Battery, 0, 100, "%.0f%%")
+	batteryDial.Band(0, 10, red)
+	batteryDial.Band(10, 25, yellow)
+
+	memoryMeter := gauge.NewMeter(program, 660, graphs.Texture.Y-110, 200, 20, stats.Memory, 0, 100, "%.0f%% RAM")
+	memoryMeter.Band(75, 90, yellow)
+	memoryMeter.Band(90, 100, red)
+
+	fanLevel := gauge.NewMeter(program, 780, graphs.Texture.Y, 30, 100, stats.FanLevel, 0, 8, "L%.0f")
+	fanLevel.Ticks = 9
+	fanLevel.Unknown = "auto"
+	fanLevel.Band(7, 8, red)
+
+	batteries := battery.New(program, 340, graphs.Texture.Y-130, 360, 100, stats)
+	interfaces := network.New(program, 340, graphs.Texture.Y-240, 360, 100, stats, cfg.Network)
+	disks := disk.New(program, 720, graphs.Texture.Y-240, 300, 220, stats)
+	stalls := pressure.New(program, 340, graphs.Texture.Y-graphs.Texture.Height-90, 300, 70, stats)
+	processTable = process.New(program, 660, graphs.Texture.Y-graphs.Texture.Height-90, 560, 160, stats)
+	breakdown := memory.New(program, 20, graphs.Texture.Y-graphs.Texture.Height-90, 300, 70, stats)
 
 	// Configure global settings
 	gl.Enable(gl.DEPTH_TEST)
@@ -140,14 +279,38 @@ func main() {
 			glfw.PollEvents()
 			continue
 		case <-stats.Updated:
+			stats.Lock()
+			status.Lock()
 			status.Render()
+			traffic.Rows = status.Rates
+			traffic.Render()
+			status.Unlock()
 			graphs.Render()
+			cores.Render()
+			sensors.Render()
+			batteryDial.Render()
+			memoryMeter.Render()
+			fanLevel.Render()
+			batteries.Render()
+			interfaces.Render()
+			disks.Render()
+			breakdown.Render()
+			stalls.Render()
+			processTable.Render()
+			stats.Unlock()
 		case <-status.Redraw:
+			stats.Lock()
+			status.Lock()
 			status.Render()
+			status.Unlock()
+			stats.Unlock()
 		case <-maxRenderDelayTimer.C:
 			//fmt.Println("max delay tick")
 		case <-redrawChan:
 			//fmt.Println("redraw tick")
+		case <-signals:
+			window.SetShouldClose(true)
+			continue
 		}
 
 		//fmt.Println("DRAW")
@@ -157,8 +320,26 @@ func main() {
 		//foo.Texture.Draw()
 		status.Texture.Draw()
 		graphs.Texture.Draw()
+		cores.Texture.Draw()
+		sensors.Texture.Draw()
+		traffic.Texture.Draw()
+		batteryDial.Texture.Draw()
+		memoryMeter.Texture.Draw()
+		fanLevel.Texture.Draw()
+		batteries.Texture.Draw()
+		interfaces.Texture.Draw()
+		disks.Texture.Draw()
+		breakdown.Texture.Draw()
+		stalls.Texture.Draw()
+		processTable.Texture.Draw()
 
 		window.SwapBuffers()
 		glfw.PollEvents()
 	}
+
+	status.Stop()
+	if err := status.Accounting.Save(); err != nil {
+		log.Println("failed to save traffic totals:", err)
+	}
+	releaseFan()
 }
@@ -0,0 +1,195 @@
+package widgets
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Traffic is a number of bytes received and sent.
+type Traffic struct {
+	Recv uint64 `json:"recv"`
+	Sent uint64 `json:"sent"`
+}
+
+func (t Traffic) Total() uint64 {
+	return t.Recv + t.Sent
+}
+
+// Usage is the traffic of an interface bucketed by local hour, day and month,
+// keyed as "2006-01-02T15", "2006-01-02" and "2006-01".
+type Usage struct {
+	Hours  map[string]Traffic `json:"hours"`
+	Days   map[string]Traffic `json:"days"`
+	Months map[string]Traffic `json:"months"`
+}
+
+const (
+	hourKey  = "2006-01-02T15"
+	dayKey   = "2006-01-02"
+	monthKey = "2006-01"
+)
+
+// How many buckets of each kind are kept.
+var (
+	KeepHours  int = 48
+	KeepDays   int = 62
+	KeepMonths int = 24
+)
+
+func newUsage() *Usage {
+	return &Usage{Hours: map[string]Traffic{}, Days: map[string]Traffic{}, Months: map[string]Traffic{}}
+}
+
+func (u *Usage) add(t time.Time, traffic Traffic) {
+	for _, bucket := range []struct {
+		buckets map[string]Traffic
+		key     string
+		keep    int
+	}{
+		{u.Hours, t.Format(hourKey), KeepHours},
+		{u.Days, t.Format(dayKey), KeepDays},
+		{u.Months, t.Format(monthKey), KeepMonths},
+	} {
+		total := bucket.buckets[bucket.key]
+		total.Recv += traffic.Recv
+		total.Sent += traffic.Sent
+		bucket.buckets[bucket.key] = total
+		prune(bucket.buckets, bucket.keep)
+	}
+}
+
+// prune drops the oldest buckets beyond keep, the keys sort by time.
+func prune(buckets map[string]Traffic, keep int) {
+	if len(buckets) <= keep {
+		return
+	}
+	keys := make([]string, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys[:len(keys)-keep] {
+		delete(buckets, key)
+	}
+}
+
+// Accounting keeps per interface traffic totals in a JSON file, like vnstat.
+//
+// Totals are added up from the growth of the interface counters, so they
+// survive restarts of harvey and resets of the counters. After a reset the
+// counter is counted from zero. Traffic between the last sample before a
+// restart and the first one after it is lost.
+type Accounting struct {
+	Path       string
+	Interfaces map[string]*Usage
+
+	last  map[string]Traffic
+	saved time.Time
+}
+
+// LoadAccounting reads the totals at path, a missing file starts empty.
+func LoadAccounting(path string) (*Accounting, error) {
+	a := &Accounting{Path: path, Interfaces: map[string]*Usage{}, last: map[string]Traffic{}}
+
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return a, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(buf, &a.Interfaces); err != nil {
+		return nil, err
+	}
+	for name, usage := range a.Interfaces {
+		empty := newUsage()
+		if usage == nil {
+			a.Interfaces[name] = empty
+			continue
+		}
+		if usage.Hours == nil {
+			usage.Hours = empty.Hours
+		}
+		if usage.Days == nil {
+			usage.Days = empty.Days
+		}
+		if usage.Months == nil {
+			usage.Months = empty.Months
+		}
+	}
+	return a, nil
+}
+
+// Add records the counters of an interface at t.
+func (a *Accounting) Add(iface string, recv, sent uint64, t time.Time) {
+	last, seen := a.last[iface]
+	a.last[iface] = Traffic{Recv: recv, Sent: sent}
+	if !seen {
+		return
+	}
+
+	diff := func(last, value uint64) uint64 {
+		if d, ok := counterDiff(last, value); ok {
+			return d
+		}
+		return value
+	}
+
+	usage, ok := a.Interfaces[iface]
+	if !ok {
+		usage = newUsage()
+		a.Interfaces[iface] = usage
+	}
+	usage.add(t, Traffic{Recv: diff(last.Recv, recv), Sent: diff(last.Sent, sent)})
+}
+
+// Forget drops the last counters of an interface that went away, so that it
+// is counted from its first sample when it comes back.
+func (a *Accounting) Forget(iface string) {
+	delete(a.last, iface)
+}
+
+// Day and Month return the traffic of an interface in the day or month of t.
+func (a *Accounting) Day(iface string, t time.Time) Traffic {
+	if usage, ok := a.Interfaces[iface]; ok {
+		return usage.Days[t.Format(dayKey)]
+	}
+	return Traffic{}
+}
+
+func (a *Accounting) Month(iface string, t time.Time) Traffic {
+	if usage, ok := a.Interfaces[iface]; ok {
+		return usage.Months[t.Format(monthKey)]
+	}
+	return Traffic{}
+}
+
+// Save writes the totals, through a temporary file so that a crash doesn't
+// leave a truncated file behind.
+func (a *Accounting) Save() error {
+	buf, err := json.MarshalIndent(a.Interfaces, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(a.Path), 0755); err != nil {
+		return err
+	}
+
+	tmp := a.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf, 0644); err != nil {
+		return err
+	}
+	a.saved = time.Now()
+	return os.Rename(tmp, a.Path)
+}
+
+// SaveEvery saves the totals when the last save is older than interval.
+func (a *Accounting) SaveEvery(interval time.Duration) error {
+	if time.Since(a.saved) < interval {
+		return nil
+	}
+	return a.Save()
+}
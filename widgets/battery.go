@@ -0,0 +1,265 @@
+package widgets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// BatteryStatus is a single battery, or the combined pack of all of them.
+//
+// The kernel reports batteries either energy based (ENERGY_* in µWh and
+// POWER_NOW in µW) or charge based (CHARGE_* in µAh and CURRENT_NOW in µA).
+// Both are converted to Wh and W here, using the voltage for charge based
+// batteries, so that batteries of either kind can be combined. The charge
+// based values are kept as reported, in mAh and A.
+type BatteryStatus struct {
+	BatteryID   string
+	Status      string
+	EnergyBased bool
+
+	Energy     float64 // Wh
+	EnergyFull float64 // Wh
+	Power      float64 // W
+
+	Charge     float64 // mAh
+	ChargeFull float64 // mAh
+	Current    float64 // A
+	Voltage    float64 // V
+
+	Percent   float64
+	Remaining string
+
+	// EnergyFullDesign and ChargeFullDesign are the capacities the battery
+	// was built for, Health is the full capacity relative to them.
+	EnergyFullDesign float64 // Wh
+	ChargeFullDesign float64 // mAh
+	Health           float64
+	CycleCount       int
+
+	Technology   string
+	Manufacturer string
+	ModelName    string
+
+	// ChargeStartThreshold and ChargeEndThreshold are the percentages the
+	// firmware starts and stops charging at, 0 when not supported.
+	ChargeStartThreshold int
+	ChargeEndThreshold   int
+}
+
+func (b BatteryStatus) Charging() bool {
+	return b.Status == "Charging"
+}
+
+const batteryPath = "/sys/class/power_supply"
+
+func ReadBatteries() ([]BatteryStatus, error) {
+	supplies, err := ReadPowerSupplies()
+	if err != nil {
+		return nil, err
+	}
+	return Batteries(supplies), nil
+}
+
+func ReadBattery(name string) (*BatteryStatus, error) {
+	vars, err := readUevent(name)
+	if err != nil {
+		return nil, err
+	}
+	return parseBattery(name, vars), nil
+}
+
+func readUevent(name string) (map[string]string, error) {
+	file, err := ioutil.ReadFile(fmt.Sprintf("%s/%s/uevent", batteryPath, name))
+	if err != nil {
+		return nil, err
+	}
+	return parseUevent(string(file)), nil
+}
+
+func parseUevent(uevent string) map[string]string {
+	vars := map[string]string{}
+
+	for _, line := range strings.Split(uevent, "\n") {
+		buf := strings.SplitN(line, "=", 2)
+		if len(buf) == 2 {
+			vars[buf[0]] = buf[1]
+		}
+	}
+	return vars
+}
+
+// micro reads a value the kernel reports in millionths of its unit.
+func micro(vars map[string]string, key string) (float64, bool) {
+	value, err := strconv.ParseInt(vars[key], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if value < 0 {
+		// Some drivers report the current as negative while discharging.
+		value = -value
+	}
+	return float64(value) / 1000000, true
+}
+
+func parseBattery(name string, vars map[string]string) *BatteryStatus {
+	battery := &BatteryStatus{
+		BatteryID: name,
+		Status:    vars["POWER_SUPPLY_STATUS"],
+	}
+
+	battery.Voltage, _ = micro(vars, "POWER_SUPPLY_VOLTAGE_NOW")
+
+	if energyFull, ok := micro(vars, "POWER_SUPPLY_ENERGY_FULL"); ok {
+		battery.EnergyBased = true
+		battery.EnergyFull = energyFull
+		battery.Energy, _ = micro(vars, "POWER_SUPPLY_ENERGY_NOW")
+		battery.EnergyFullDesign, _ = micro(vars, "POWER_SUPPLY_ENERGY_FULL_DESIGN")
+		battery.Power, _ = micro(vars, "POWER_SUPPLY_POWER_NOW")
+		if battery.Voltage > 0 {
+			battery.Current = battery.Power / battery.Voltage
+			battery.Charge = battery.Energy / battery.Voltage * 1000
+			battery.ChargeFull = battery.EnergyFull / battery.Voltage * 1000
+			battery.ChargeFullDesign = battery.EnergyFullDesign / battery.Voltage * 1000
+		}
+	} else if chargeFull, ok := micro(vars, "POWER_SUPPLY_CHARGE_FULL"); ok {
+		charge, _ := micro(vars, "POWER_SUPPLY_CHARGE_NOW")
+		chargeFullDesign, _ := micro(vars, "POWER_SUPPLY_CHARGE_FULL_DESIGN")
+		battery.Charge = charge * 1000
+		battery.ChargeFull = chargeFull * 1000
+		battery.ChargeFullDesign = chargeFullDesign * 1000
+		battery.Current, _ = micro(vars, "POWER_SUPPLY_CURRENT_NOW")
+
+		// The charge is converted at the design voltage where available, the
+		// current voltage sags with load.
+		voltage, ok := micro(vars, "POWER_SUPPLY_VOLTAGE_MIN_DESIGN")
+		if !ok {
+			voltage = battery.Voltage
+		}
+		battery.Energy = charge * voltage
+		battery.EnergyFull = chargeFull * voltage
+		battery.EnergyFullDesign = chargeFullDesign * voltage
+		battery.Power = battery.Current * battery.Voltage
+	}
+
+	if battery.EnergyFull > 0 {
+		battery.Percent = (battery.Energy * 100.0) / battery.EnergyFull
+	} else if capacity, err := strconv.ParseFloat(vars["POWER_SUPPLY_CAPACITY"], 64); err == nil {
+		battery.Percent = capacity
+	}
+
+	battery.Health = battery.health()
+
+	battery.CycleCount, _ = strconv.Atoi(vars["POWER_SUPPLY_CYCLE_COUNT"])
+	battery.Technology = vars["POWER_SUPPLY_TECHNOLOGY"]
+	battery.Manufacturer = vars["POWER_SUPPLY_MANUFACTURER"]
+	battery.ModelName = vars["POWER_SUPPLY_MODEL_NAME"]
+
+	// The thresholds aren't part of uevent, older thinkpad_acpi versions
+	// name them without the control_ infix.
+	battery.ChargeStartThreshold = readThreshold(name, "charge_control_start_threshold", "charge_start_threshold")
+	battery.ChargeEndThreshold = readThreshold(name, "charge_control_end_threshold", "charge_stop_threshold")
+
+	if battery.Status == "Unknown" {
+		battery.Status = "Idle"
+	}
+
+	battery.Remaining = battery.remaining()
+
+	return battery
+}
+
+func readThreshold(name string, files ...string) int {
+	for _, file := range files {
+		buf, err := ioutil.ReadFile(fmt.Sprintf("%s/%s/%s", batteryPath, name, file))
+		if err != nil {
+			continue
+		}
+		if value, err := strconv.Atoi(strings.TrimSpace(string(buf))); err == nil {
+			return value
+		}
+	}
+	return 0
+}
+
+// health is the full capacity in percent of the design capacity, in energy
+// where known and in charge otherwise; without a voltage to convert at, a
+// charge based battery only knows its charge.
+func (b *BatteryStatus) health() float64 {
+	switch {
+	case b.EnergyFull > 0 && b.EnergyFullDesign > 0:
+		return (b.EnergyFull * 100.0) / b.EnergyFullDesign
+	case b.ChargeFull > 0 && b.ChargeFullDesign > 0:
+		return (b.ChargeFull * 100.0) / b.ChargeFullDesign
+	}
+	return 0
+}
+
+// remaining formats the time to empty, or to full while charging, as hh:mm.
+func (b *BatteryStatus) remaining() string {
+	if b.Power <= 0 {
+		return "00:00"
+	}
+
+	hours := 0.0
+	if b.Charging() {
+		hours = (b.EnergyFull - b.Energy) / b.Power
+	} else {
+		hours = b.Energy / b.Power
+	}
+
+	return HoursMinutes(hours * 3600)
+}
+
+// HoursMinutes formats seconds as hh:mm.
+func HoursMinutes(secs float64) string {
+	seconds := int(secs)
+	h := seconds / 3600
+	m := (seconds - (h * 3600)) / 60
+	return fmt.Sprintf("%.2d:%.2d", h, m)
+}
+
+// CombineBatteries sums the batteries of a multi-battery laptop into a single
+// pack. The pack is charging if any battery is, discharging if any battery
+// is, and idle otherwise. Its cycle count is that of the most worn battery.
+func CombineBatteries(batteries []BatteryStatus) *BatteryStatus {
+	if len(batteries) == 0 {
+		return nil
+	}
+	if len(batteries) == 1 {
+		pack := batteries[0]
+		return &pack
+	}
+
+	pack := &BatteryStatus{BatteryID: "pack", Status: "Idle", EnergyBased: true}
+	for _, b := range batteries {
+		pack.Energy += b.Energy
+		pack.EnergyFull += b.EnergyFull
+		pack.Power += b.Power
+		pack.Charge += b.Charge
+		pack.ChargeFull += b.ChargeFull
+		pack.Current += b.Current
+		pack.EnergyFullDesign += b.EnergyFullDesign
+		pack.ChargeFullDesign += b.ChargeFullDesign
+		if b.CycleCount > pack.CycleCount {
+			pack.CycleCount = b.CycleCount
+		}
+
+		switch {
+		case b.Status == "Charging":
+			pack.Status = b.Status
+		case b.Status == "Discharging" && pack.Status != "Charging":
+			pack.Status = b.Status
+		case b.Status == "Full" && pack.Status == "Idle":
+			pack.Status = b.Status
+		}
+	}
+
+	if pack.EnergyFull > 0 {
+		pack.Percent = (pack.Energy * 100.0) / pack.EnergyFull
+	}
+	pack.Health = pack.health()
+	pack.Remaining = pack.remaining()
+	return pack
+}
@@ -0,0 +1,94 @@
+package battery
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+	"github.com/maurodelazeri/harvey-gl/widgets/graph"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Details lists every battery with its wear and charge thresholds, one block
+// of lines per battery followed by a health bar.
+type Details struct {
+	Texture *texture.Texture
+	Stats   *widgets.Stats
+
+	Color      color.RGBA
+	Background color.RGBA
+}
+
+func New(program *shader.Program, x, y, width, height float64, stats *widgets.Stats) *Details {
+	d := &Details{
+		Texture:    &texture.Texture{X: x, Y: y, Width: width, Height: height},
+		Stats:      stats,
+		Color:      color.RGBA{0x99, 0x99, 0x99, 0xff},
+		Background: color.RGBA{0x33, 0x33, 0x33, 0xff},
+	}
+	d.Texture.Setup(program)
+	return d
+}
+
+func lines(b widgets.BatteryStatus) []string {
+	name := strings.TrimSpace(strings.Join([]string{b.BatteryID, b.Manufacturer, b.ModelName, b.Technology}, " "))
+
+	capacity := fmt.Sprintf("%.1f/%.1f Wh", b.EnergyFull, b.EnergyFullDesign)
+	if !b.EnergyBased {
+		capacity = fmt.Sprintf("%.0f/%.0f mAh", b.ChargeFull, b.ChargeFullDesign)
+	}
+
+	thresholds := "thresholds n/a"
+	if b.ChargeEndThreshold > 0 {
+		thresholds = fmt.Sprintf("charges %d-%d%%", b.ChargeStartThreshold, b.ChargeEndThreshold)
+	}
+
+	return []string{
+		name,
+		fmt.Sprintf("health %.0f%% %s  cycles %d", b.Health, capacity, b.CycleCount),
+		fmt.Sprintf("%s %.0f%% %.1fW %sh  %s", strings.ToLower(b.Status), b.Percent, b.Power, b.Remaining, thresholds),
+	}
+}
+
+func (d *Details) Render() {
+	data := image.NewRGBA(image.Rect(0, 0, int(d.Texture.Width), int(d.Texture.Height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(d.Background)
+	draw2dkit.Rectangle(gc, 0, 0, d.Texture.Width, d.Texture.Height)
+	gc.Fill()
+
+	y := 2
+	if len(d.Stats.Batteries) == 0 {
+		font.DrawString(data, font.Width, y, "no battery", d.Color)
+	}
+
+	bg := color.RGBA{0x44, 0x44, 0x44, 0xff}
+	if e := d.Stats.Estimate; e.Valid {
+		verb := "empty"
+		if e.Charging {
+			verb = "full"
+		}
+		line := fmt.Sprintf("%s in %s (%s-%s) at %.1fW avg", verb, widgets.HoursMinutes(e.Seconds), widgets.HoursMinutes(e.Low), widgets.HoursMinutes(e.High), e.Rate)
+		font.DrawString(data, font.Width, y, line, d.Color)
+		y += font.Height
+	}
+
+	for _, b := range d.Stats.Batteries {
+		for _, line := range lines(b) {
+			font.DrawString(data, font.Width, y, line, d.Color)
+			y += font.Height
+		}
+		graph.ProgressBar(gc, b.Health/100, font.Width, float64(y+2), d.Texture.Width-(font.Width*2), 4, d.Color, bg)
+		y += font.Height
+	}
+
+	d.Texture.Write(&data.Pix)
+}
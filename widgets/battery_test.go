@@ -0,0 +1,105 @@
+package widgets
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const energyUevent = `POWER_SUPPLY_NAME=BAT0
+POWER_SUPPLY_STATUS=Discharging
+POWER_SUPPLY_VOLTAGE_NOW=12000000
+POWER_SUPPLY_POWER_NOW=6000000
+POWER_SUPPLY_ENERGY_FULL_DESIGN=50000000
+POWER_SUPPLY_ENERGY_FULL=40000000
+POWER_SUPPLY_ENERGY_NOW=30000000
+POWER_SUPPLY_CAPACITY=75
+`
+
+// chargeUevent is a battery that reports its current as negative while
+// discharging.
+const chargeUevent = `POWER_SUPPLY_NAME=BAT1
+POWER_SUPPLY_STATUS=Discharging
+POWER_SUPPLY_VOLTAGE_MIN_DESIGN=11100000
+POWER_SUPPLY_VOLTAGE_NOW=12000000
+POWER_SUPPLY_CURRENT_NOW=-1500000
+POWER_SUPPLY_CHARGE_FULL_DESIGN=4000000
+POWER_SUPPLY_CHARGE_FULL=3600000
+POWER_SUPPLY_CHARGE_NOW=1800000
+POWER_SUPPLY_CAPACITY=50
+`
+
+// without drops the lines of the given keys from a uevent.
+func without(uevent string, keys ...string) string {
+	lines := []string{}
+	for _, line := range strings.Split(uevent, "\n") {
+		keep := true
+		for _, key := range keys {
+			if strings.HasPrefix(line, key+"=") {
+				keep = false
+			}
+		}
+		if keep {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestParseBattery(t *testing.T) {
+	tests := []struct {
+		name   string
+		uevent string
+		want   BatteryStatus
+	}{
+		{"energy", energyUevent, BatteryStatus{
+			EnergyBased: true,
+			Energy:      30, EnergyFull: 40, EnergyFullDesign: 50, Power: 6,
+			Charge: 2500, ChargeFull: 40000.0 / 12, ChargeFullDesign: 50000.0 / 12, Current: 0.5, Voltage: 12,
+			Percent: 75, Health: 80, Remaining: "05:00",
+		}},
+		{"charge", chargeUevent, BatteryStatus{
+			Energy: 1.8 * 11.1, EnergyFull: 3.6 * 11.1, EnergyFullDesign: 4 * 11.1, Power: 18,
+			Charge: 1800, ChargeFull: 3600, ChargeFullDesign: 4000, Current: 1.5, Voltage: 12,
+			Percent: 50, Health: 90, Remaining: "01:06",
+		}},
+		{"charge without design voltage", without(chargeUevent, "POWER_SUPPLY_VOLTAGE_MIN_DESIGN"), BatteryStatus{
+			Energy: 1.8 * 12, EnergyFull: 3.6 * 12, EnergyFullDesign: 4 * 12, Power: 18,
+			Charge: 1800, ChargeFull: 3600, ChargeFullDesign: 4000, Current: 1.5, Voltage: 12,
+			Percent: 50, Health: 90, Remaining: "01:12",
+		}},
+		{"charge without voltage", without(chargeUevent, "POWER_SUPPLY_VOLTAGE_MIN_DESIGN", "POWER_SUPPLY_VOLTAGE_NOW"), BatteryStatus{
+			Charge: 1800, ChargeFull: 3600, ChargeFullDesign: 4000, Current: 1.5,
+			Percent: 50, Health: 90, Remaining: "00:00",
+		}},
+	}
+	for _, test := range tests {
+		got := parseBattery("test", parseUevent(test.uevent))
+		if got.Status != "Discharging" || got.EnergyBased != test.want.EnergyBased || got.Remaining != test.want.Remaining {
+			t.Errorf("%s: got %s energy based %v remaining %s, want Discharging %v %s", test.name,
+				got.Status, got.EnergyBased, got.Remaining, test.want.EnergyBased, test.want.Remaining)
+		}
+
+		fields := []struct {
+			name      string
+			got, want float64
+		}{
+			{"energy", got.Energy, test.want.Energy},
+			{"energy full", got.EnergyFull, test.want.EnergyFull},
+			{"energy full design", got.EnergyFullDesign, test.want.EnergyFullDesign},
+			{"power", got.Power, test.want.Power},
+			{"charge", got.Charge, test.want.Charge},
+			{"charge full", got.ChargeFull, test.want.ChargeFull},
+			{"charge full design", got.ChargeFullDesign, test.want.ChargeFullDesign},
+			{"current", got.Current, test.want.Current},
+			{"voltage", got.Voltage, test.want.Voltage},
+			{"percent", got.Percent, test.want.Percent},
+			{"health", got.Health, test.want.Health},
+		}
+		for _, field := range fields {
+			if math.Abs(field.got-field.want) > 1e-9 {
+				t.Errorf("%s: got %s %v, want %v", test.name, field.name, field.got, field.want)
+			}
+		}
+	}
+}
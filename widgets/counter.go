@@ -0,0 +1,70 @@
+package widgets
+
+import (
+	"math"
+	"time"
+)
+
+// Counter turns samples of a cumulative counter, like the bytes received by
+// an interface, into rates.
+//
+// A counter going backwards has either wrapped or been reset, e.g. by the
+// interface being recreated. Counters are read as 64 bit, but some drivers
+// still wrap at 32 bit, so a counter that was in the upper quarter of the 32
+// bit range and went back is taken as wrapped. Anything else going backwards
+// is a reset. A reset, a wrap that would mean a rate above MaxRate, and a gap
+// longer than MaxGap since the previous sample, e.g. after a suspend, all
+// yield an unknown rate instead of a spike.
+type Counter struct {
+	MaxGap  time.Duration
+	MaxRate float64
+
+	last uint64
+	time time.Time
+	seen bool
+}
+
+func NewCounter(maxGap time.Duration) *Counter {
+	return &Counter{MaxGap: maxGap}
+}
+
+const wrap32 = 1 << 32
+
+// Update records the counter value at t and returns the rate per second since
+// the previous value, or NaN when it is unknown.
+func (c *Counter) Update(value uint64, t time.Time) float64 {
+	last, lastTime, seen := c.last, c.time, c.seen
+	c.last, c.time, c.seen = value, t, true
+
+	if !seen {
+		return math.NaN()
+	}
+	elapsed := t.Sub(lastTime)
+	if elapsed <= 0 || (c.MaxGap > 0 && elapsed > c.MaxGap) {
+		return math.NaN()
+	}
+
+	diff, ok := counterDiff(last, value)
+	if !ok {
+		return math.NaN()
+	}
+
+	rate := float64(diff) / elapsed.Seconds()
+	if c.MaxRate > 0 && rate > c.MaxRate {
+		return math.NaN()
+	}
+	return rate
+}
+
+// counterDiff returns how much a counter grew from last to value, false when
+// it was reset.
+func counterDiff(last, value uint64) (uint64, bool) {
+	switch {
+	case value >= last:
+		return value - last, true
+	case last < wrap32 && last >= wrap32/4*3:
+		return wrap32 - last + value, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,49 @@
+package widgets
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCounter(t *testing.T) {
+	type sample struct {
+		value  uint64
+		offset time.Duration
+	}
+	nan := math.NaN()
+	tests := []struct {
+		name    string
+		maxRate float64
+		samples []sample
+		want    float64
+	}{
+		{"first sample", 0, []sample{{100, 0}}, nan},
+		{"rate", 0, []sample{{100, 0}, {1100, 10 * time.Second}}, 100},
+		{"unchanged", 0, []sample{{100, 0}, {100, time.Second}}, 0},
+		{"reset", 0, []sample{{5000, 0}, {10, time.Second}}, nan},
+		{"rate after reset", 0, []sample{{5000, 0}, {10, time.Second}, {110, 2 * time.Second}}, 100},
+		{"wrap at 32 bit", 0, []sample{{wrap32 - 100, 0}, {100, time.Second}}, 200},
+		{"reset below the upper quarter", 0, []sample{{wrap32 / 2, 0}, {100, time.Second}}, nan},
+		{"reset of a 64 bit counter", 0, []sample{{wrap32 * 2, 0}, {100, time.Second}}, nan},
+		{"wrap above max rate", 100, []sample{{wrap32 / 4 * 3, 0}, {100, time.Second}}, nan},
+		{"gap", 0, []sample{{100, 0}, {200, time.Minute}}, nan},
+		{"rate after gap", 0, []sample{{100, 0}, {200, time.Minute}, {300, time.Minute + time.Second}}, 100},
+		{"same time", 0, []sample{{100, 0}, {200, 0}}, nan},
+		{"backwards in time", 0, []sample{{100, time.Second}, {200, 0}}, nan},
+	}
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		c := NewCounter(30 * time.Second)
+		c.MaxRate = test.maxRate
+
+		got := 0.0
+		for _, s := range test.samples {
+			got = c.Update(s.value, start.Add(s.offset))
+		}
+		if got != test.want && !(math.IsNaN(got) && math.IsNaN(test.want)) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package widgets
+
+import (
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Disk is the I/O history of a block device. Read and Write are in MiB/s, Ops
+// counts reads and writes per second and Util is the share of time the
+// device was busy.
+type Disk struct {
+	Name  string
+	Read  *Series
+	Write *Series
+	Ops   *Series
+	Util  *Series
+
+	read  *Counter
+	write *Counter
+	ops   *Counter
+	busy  *Counter
+}
+
+// DiskMaxGap is the longest time between two samples of a device that still
+// gives a rate.
+var DiskMaxGap time.Duration = time.Second * 30
+
+func newDisk(name string) *Disk {
+	return &Disk{
+		Name:  name,
+		Read:  NewSeries(name+" read", "MiB/s", 60),
+		Write: NewSeries(name+" write", "MiB/s", 60),
+		Ops:   NewSeries(name+" ops", "IOPS", 60),
+		Util:  NewSeries(name+" util", "%", 60),
+		read:  NewCounter(DiskMaxGap),
+		write: NewCounter(DiskMaxGap),
+		ops:   NewCounter(DiskMaxGap),
+		busy:  NewCounter(DiskMaxGap),
+	}
+}
+
+// update parses a line of /proc/diskstats:
+//
+//	259 0 nvme0n1 reads merged sectors ms writes merged sectors ms inflight ms_io ...
+//
+// Sectors are 512 bytes regardless of the device.
+func (d *Disk) update(fields []string, now time.Time) {
+	value := func(i int) uint64 {
+		v, _ := strconv.ParseUint(fields[i], 10, 64)
+		return v
+	}
+
+	mib := func(rate float64) float64 { return rate * 512 / (1 << 20) }
+	d.Read.PushAt(mib(d.read.Update(value(5), now)), now)
+	d.Write.PushAt(mib(d.write.Update(value(9), now)), now)
+	d.Ops.PushAt(d.ops.Update(value(3)+value(7), now), now)
+	// ms busy per second, as percent.
+	d.Util.PushAt(math.Min(d.busy.Update(value(12), now)/10, 100), now)
+}
+
+// Mount is the usage of a mounted filesystem, in bytes and inodes.
+type Mount struct {
+	Path string
+
+	Total uint64
+	Free  uint64
+	Used  uint64
+
+	Inodes     uint64
+	InodesFree uint64
+
+	// Usage and InodeUsage are the used shares in percent, Usage with its
+	// history.
+	Usage      *Series
+	InodeUsage float64
+}
+
+func newMount(path string) *Mount {
+	return &Mount{Path: path, Usage: NewSeries(path, "%", 60)}
+}
+
+// update reads the usage like df, the space reserved for root counts as
+// used.
+func (m *Mount) update() error {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(m.Path, &fs); err != nil {
+		return err
+	}
+
+	m.Total = fs.Blocks * uint64(fs.Bsize)
+	m.Free = fs.Bavail * uint64(fs.Bsize)
+	m.Used = (fs.Blocks - fs.Bfree) * uint64(fs.Bsize)
+	if usable := m.Used + m.Free; usable > 0 {
+		m.Usage.Push(float64(m.Used) * 100 / float64(usable))
+	}
+
+	m.Inodes = fs.Files
+	m.InodesFree = fs.Ffree
+	if m.Inodes > 0 {
+		m.InodeUsage = float64(m.Inodes-m.InodesFree) * 100 / float64(m.Inodes)
+	}
+	return nil
+}
+
+func (s *Stats) UpdateDisks() {
+	buf, err := ioutil.ReadFile("/proc/diskstats")
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, line := range strings.Split(string(buf), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 14 || !s.Disk.Selects(fields[2]) {
+			continue
+		}
+
+		disk := s.disk(fields[2])
+		disk.update(fields, now)
+	}
+}
+
+// disk returns the device with the given name, adding it when first seen.
+func (s *Stats) disk(name string) *Disk {
+	for _, disk := range s.Disks {
+		if disk.Name == name {
+			return disk
+		}
+	}
+	disk := newDisk(name)
+	s.Disks = append(s.Disks, disk)
+	return disk
+}
+
+func (s *Stats) UpdateMounts() {
+	if s.Mounts == nil {
+		for _, path := range s.Disk.Mounts {
+			s.Mounts = append(s.Mounts, newMount(path))
+		}
+	}
+
+	for _, mount := range s.Mounts {
+		mount.update()
+	}
+}
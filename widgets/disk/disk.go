@@ -0,0 +1,91 @@
+package disk
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+	"github.com/maurodelazeri/harvey-gl/widgets/graph"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Disks shows the usage of every mount as a bar, followed by a graph of the
+// reads of every device with its writes overlaid.
+type Disks struct {
+	Texture *texture.Texture
+	Stats   *widgets.Stats
+
+	GraphHeight float64
+	Color       color.RGBA
+	WriteColor  color.RGBA
+	Background  color.RGBA
+
+	graphs map[string]*graph.Graph
+}
+
+func New(program *shader.Program, x, y, width, height float64, stats *widgets.Stats) *Disks {
+	d := &Disks{
+		Texture:     &texture.Texture{X: x, Y: y, Width: width, Height: height},
+		Stats:       stats,
+		GraphHeight: 40,
+		Color:       color.RGBA{0x99, 0x99, 0x99, 0xff},
+		WriteColor:  color.RGBA{0x99, 0x66, 0x33, 0xff},
+		Background:  color.RGBA{0x33, 0x33, 0x33, 0xff},
+		graphs:      map[string]*graph.Graph{},
+	}
+	d.Texture.Setup(program)
+	return d
+}
+
+func gib(bytes uint64) float64 {
+	return float64(bytes) / (1 << 30)
+}
+
+// graph returns the graph of the device, creating it when first drawn.
+func (d *Disks) graph(disk *widgets.Disk) *graph.Graph {
+	if g, ok := d.graphs[disk.Name]; ok {
+		return g
+	}
+	g := graph.New(disk.Read, disk.Name+" r %.1f MiB/s", d.Texture.Width-float64(font.Width*2), d.GraphHeight).
+		Scaled(graph.ScaleNice).
+		Overlay(disk.Write, "w %.1f MiB/s", d.WriteColor, false)
+	d.graphs[disk.Name] = g
+	return g
+}
+
+func (d *Disks) Render() {
+	data := image.NewRGBA(image.Rect(0, 0, int(d.Texture.Width), int(d.Texture.Height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(d.Background)
+	draw2dkit.Rectangle(gc, 0, 0, d.Texture.Width, d.Texture.Height)
+	gc.Fill()
+
+	y := 2
+	bg := color.RGBA{0x44, 0x44, 0x44, 0xff}
+	for _, mount := range d.Stats.Mounts {
+		line := fmt.Sprintf("%s %.1f/%.1f GiB free, inodes %.0f%%", mount.Path, gib(mount.Free), gib(mount.Total), mount.InodeUsage)
+		font.DrawString(data, font.Width, y, line, d.Color)
+		y += font.Height
+		graph.ProgressBar(gc, mount.Usage.Value/100, font.Width, float64(y+2), d.Texture.Width-(font.Width*2), 4, d.Color, bg)
+		y += font.Height
+	}
+
+	for _, disk := range d.Stats.Disks {
+		g := d.graph(disk)
+		g.Draw(gc, data, font.Width, float64(y))
+		y += int(g.Height) + 4
+
+		line := fmt.Sprintf("%.0f IOPS  %.0f%% busy", disk.Ops.Value, disk.Util.Value)
+		font.DrawString(data, font.Width, y, line, d.Color)
+		y += font.Height + 4
+	}
+
+	d.Texture.Write(&data.Pix)
+}
@@ -0,0 +1,120 @@
+package widgets
+
+import (
+	"math"
+	"time"
+)
+
+// Estimate is the smoothed time until the battery is empty, or full while
+// charging, with a range covering two standard deviations of the rate.
+type Estimate struct {
+	Valid    bool
+	Charging bool
+	Rate     float64 // W
+	Seconds  float64
+	Low      float64
+	High     float64
+}
+
+// maxEstimate caps estimates when the rate is close to zero.
+const maxEstimate = 99 * 3600.0
+
+// Estimator smooths the charge or discharge rate of a battery with an
+// exponentially weighted moving average, so that the estimate follows the
+// average load instead of jumping with the instantaneous power_now.
+//
+// The rate is sampled from power_now, or for drivers that don't report it
+// from the change of the stored energy since it last moved. After the status
+// changes, e.g. on plugging in or pulling the AC adapter, the history is
+// dropped and the first samples are averaged evenly, so the estimate settles
+// within a few updates instead of a full time constant.
+type Estimator struct {
+	// TimeConstant is how long it takes for a change in load to be 63%
+	// reflected in the rate.
+	TimeConstant time.Duration
+
+	status   string
+	samples  int
+	rate     float64
+	variance float64
+	time     time.Time
+
+	// energy and moved are the stored energy and when it last changed.
+	energy float64
+	moved  time.Time
+}
+
+func NewEstimator(timeConstant time.Duration) *Estimator {
+	return &Estimator{TimeConstant: timeConstant}
+}
+
+func (e *Estimator) reset(status string) {
+	e.status = status
+	e.samples = 0
+	e.rate = 0
+	e.variance = 0
+	e.time = time.Time{}
+	e.moved = time.Time{}
+}
+
+func (e *Estimator) Update(b *BatteryStatus, now time.Time) Estimate {
+	if b == nil || (b.Status != "Charging" && b.Status != "Discharging") {
+		e.reset("")
+		return Estimate{}
+	}
+	if b.Status != e.status {
+		e.reset(b.Status)
+	}
+
+	measured := b.Power
+	if b.Energy != e.energy || e.moved.IsZero() {
+		if measured <= 0 && !e.moved.IsZero() {
+			measured = math.Abs(b.Energy-e.energy) / now.Sub(e.moved).Hours()
+		}
+		e.energy = b.Energy
+		e.moved = now
+	}
+	if measured <= 0 {
+		// Nothing to learn before the first measurement, or until the
+		// energy moves again.
+		return e.estimate(b)
+	}
+
+	alpha := 1.0 / float64(e.samples+1)
+	if !e.time.IsZero() && e.TimeConstant > 0 {
+		alpha = math.Max(alpha, 1-math.Exp(-now.Sub(e.time).Seconds()/e.TimeConstant.Seconds()))
+	}
+
+	diff := measured - e.rate
+	e.rate += alpha * diff
+	e.variance = (1 - alpha) * (e.variance + (alpha * diff * diff))
+	e.samples++
+	e.time = now
+
+	return e.estimate(b)
+}
+
+func (e *Estimator) estimate(b *BatteryStatus) Estimate {
+	if e.rate <= 0 {
+		return Estimate{}
+	}
+
+	left := b.Energy
+	if b.Charging() {
+		left = b.EnergyFull - b.Energy
+	}
+
+	spread := 2 * math.Sqrt(e.variance)
+	estimate := Estimate{
+		Valid:    true,
+		Charging: b.Charging(),
+		Rate:     e.rate,
+		Seconds:  math.Min(left/e.rate*3600, maxEstimate),
+		Low:      left / (e.rate + spread) * 3600,
+		High:     maxEstimate,
+	}
+	if e.rate > spread {
+		estimate.High = math.Min(left/(e.rate-spread)*3600, maxEstimate)
+	}
+	return estimate
+}
@@ -0,0 +1,57 @@
+package widgets
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEstimator(t *testing.T) {
+	type sample struct {
+		status string
+		energy float64
+		power  float64
+		offset time.Duration
+	}
+	// A step from 10 to 20 W one time constant later is 63% reflected.
+	smoothed := 10 + (10 * (1 - math.Exp(-1)))
+	tests := []struct {
+		name     string
+		samples  []sample
+		valid    bool
+		charging bool
+		rate     float64
+		high     float64
+	}{
+		{"no measurement yet", []sample{{"Discharging", 50, 0, 0}}, false, false, 0, 0},
+		{"energy unchanged", []sample{{"Discharging", 50, 0, 0}, {"Discharging", 50, 0, time.Minute}}, false, false, 0, 0},
+		{"power", []sample{{"Discharging", 50, 10, 0}}, true, false, 10, 18000},
+		{"energy drop", []sample{{"Discharging", 50, 0, 0}, {"Discharging", 49, 0, 6 * time.Minute}}, true, false, 10, 17640},
+		{"kept until the energy moves", []sample{{"Discharging", 50, 10, 0}, {"Discharging", 50, 0, time.Minute}}, true, false, 10, 18000},
+		{"first samples averaged evenly", []sample{{"Discharging", 50, 10, 0}, {"Discharging", 50, 20, time.Second}}, true, false, 15, 36000},
+		{"smoothed over the time constant", []sample{{"Discharging", 50, 10, 0}, {"Discharging", 50, 20, 5 * time.Minute}},
+			true, false, smoothed, 50 / (smoothed - (2 * math.Sqrt(math.Exp(-1)*(1-math.Exp(-1))*100))) * 3600},
+		{"spread beyond the rate", []sample{{"Discharging", 50, 10, 0}, {"Discharging", 50, 100, time.Second}}, true, false, 55, maxEstimate},
+		{"reset on charging", []sample{{"Discharging", 50, 10, 0}, {"Charging", 50, 30, time.Second}}, true, true, 30, 1200},
+		{"reset on discharging", []sample{{"Charging", 50, 30, 0}, {"Discharging", 50, 10, time.Second}}, true, false, 10, 18000},
+		{"reset when full", []sample{{"Discharging", 50, 10, 0}, {"Full", 60, 0, time.Second}}, false, false, 0, 0},
+	}
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		e := NewEstimator(5 * time.Minute)
+
+		var got Estimate
+		for _, s := range test.samples {
+			b := &BatteryStatus{Status: s.status, Energy: s.energy, EnergyFull: 60, Power: s.power}
+			got = e.Update(b, start.Add(s.offset))
+		}
+		if got.Valid != test.valid || got.Charging != test.charging {
+			t.Errorf("%s: got valid %v charging %v, want %v %v", test.name, got.Valid, got.Charging, test.valid, test.charging)
+			continue
+		}
+		if math.Abs(got.Rate-test.rate) > 1e-6 || math.Abs(got.High-test.high) > 1e-6 {
+			t.Errorf("%s: got rate %v high %v, want %v %v", test.name, got.Rate, got.High, test.rate, test.high)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package widgets
+
+import (
+	"time"
+)
+
+// Event is something that happened at a point in time, e.g. the AC adapter
+// being plugged in, which graphs mark on their time axis.
+type Event struct {
+	Time  time.Time
+	Name  string
+	Label string
+}
+
+// Events is a bounded log of events, oldest first.
+type Events struct {
+	MaxCount int
+
+	events []Event
+}
+
+func NewEvents(maxCount int) *Events {
+	return &Events{MaxCount: maxCount}
+}
+
+func (e *Events) Add(name, label string) {
+	e.AddAt(name, label, time.Now())
+}
+
+func (e *Events) AddAt(name, label string, t time.Time) {
+	event := Event{Time: t, Name: name, Label: label}
+	if len(e.events) >= e.MaxCount {
+		e.events = append(e.events[1:], event)
+	} else {
+		e.events = append(e.events, event)
+	}
+}
+
+// Since returns a copy of the events from t on.
+func (e *Events) Since(t time.Time) []Event {
+	for i, event := range e.events {
+		if !event.Time.Before(t) {
+			return append([]Event{}, e.events[i:]...)
+		}
+	}
+	return nil
+}
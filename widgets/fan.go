@@ -0,0 +1,159 @@
+package widgets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FanSensor is a fan of a hwmon chip. Duty is the PWM duty cycle in percent,
+// for fans that have a pwmN next to their fanN_input.
+type FanSensor struct {
+	Name  string
+	Chip  string
+	Input string
+	PWM   string
+
+	RPM  *Series
+	Duty *Series
+}
+
+const hwmonPath = "/sys/class/hwmon"
+
+func readHwmonFile(path string) string {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+var fanInputRegexp *regexp.Regexp = regexp.MustCompile(`^fan(\d+)_input$`)
+
+// FindFans discovers the fans of every hwmon chip. A fan is named by its
+// fanN_label where the driver provides one, by chip and number otherwise.
+func FindFans() []*FanSensor {
+	chips, _ := filepath.Glob(filepath.Join(hwmonPath, "hwmon*"))
+	sort.Strings(chips)
+
+	var fans []*FanSensor
+	for _, chip := range chips {
+		chipName := readHwmonFile(filepath.Join(chip, "name"))
+		inputs, _ := filepath.Glob(filepath.Join(chip, "fan*_input"))
+		sort.Strings(inputs)
+
+		for _, input := range inputs {
+			m := fanInputRegexp.FindStringSubmatch(filepath.Base(input))
+			if m == nil {
+				continue
+			}
+
+			name := readHwmonFile(filepath.Join(chip, fmt.Sprintf("fan%s_label", m[1])))
+			if name == "" {
+				name = fmt.Sprintf("%s fan%s", chipName, m[1])
+			}
+
+			fan := &FanSensor{
+				Name:  name,
+				Chip:  chipName,
+				Input: input,
+				RPM:   NewSeries(name, "RPM", 60),
+				Duty:  NewSeries(name+" duty", "%", 60),
+			}
+			if pwm := filepath.Join(chip, "pwm"+m[1]); readHwmonFile(pwm) != "" {
+				fan.PWM = pwm
+			}
+			fans = append(fans, fan)
+		}
+	}
+	return fans
+}
+
+// update reads the fan and returns its speed. A sample that can't be read is
+// pushed as NaN, so that the series stay in step.
+func (f *FanSensor) update() float64 {
+	rpm := math.NaN()
+	if value, err := strconv.Atoi(readHwmonFile(f.Input)); err == nil {
+		rpm = float64(value)
+	}
+	f.RPM.Push(rpm)
+
+	if f.PWM != "" {
+		duty := math.NaN()
+		if pwm, err := strconv.Atoi(readHwmonFile(f.PWM)); err == nil {
+			duty = float64(pwm) * 100 / 255
+		}
+		f.Duty.Push(duty)
+	}
+	return rpm
+}
+
+const thinkpadFan = "/proc/acpi/ibm/fan"
+
+// MaxFanLevel is full speed, the thinkpad_acpi "full-speed" level.
+const MaxFanLevel = 8
+
+// fanLevelAuto is the level of a fan left to the firmware, which doesn't
+// tell the level it picked.
+const fanLevelAuto = -1
+
+var fanRegexp *regexp.Regexp = regexp.MustCompile("speed:\t\t(\\d+)\nlevel:\t\t(.+)")
+
+// readThinkpadFan parses /proc/acpi/ibm/fan of thinkpad_acpi, ok is false on
+// other machines. Full speed, "full-speed" or "disengaged", is reported as
+// MaxFanLevel and "auto" as fanLevelAuto.
+func readThinkpadFan() (rpm, level int, ok bool) {
+	buf, err := ioutil.ReadFile(thinkpadFan)
+	if err != nil {
+		return 0, 0, false
+	}
+	m := fanRegexp.FindStringSubmatch(string(buf))
+	if len(m) != 3 {
+		return 0, 0, false
+	}
+
+	rpm, _ = strconv.Atoi(m[1])
+	switch m[2] {
+	case "auto":
+		level = fanLevelAuto
+	case "full-speed", "disengaged":
+		level = MaxFanLevel
+	default:
+		if level, err = strconv.Atoi(m[2]); err != nil {
+			return 0, 0, false
+		}
+	}
+	return rpm, level, true
+}
+
+// UpdateFan updates every hwmon fan, and Fan with the speed of the fastest.
+// On ThinkPads FanLevel is updated too, unknown while the firmware controls
+// the fan, and Fan comes from thinkpad_acpi.
+func (s *Stats) UpdateFan() {
+	if !s.fansFound {
+		s.Fans = FindFans()
+		s.fansFound = true
+	}
+
+	rpm := math.NaN()
+	for _, fan := range s.Fans {
+		if value := fan.update(); math.IsNaN(rpm) || value > rpm {
+			rpm = value
+		}
+	}
+
+	if tpRPM, level, ok := readThinkpadFan(); ok {
+		rpm = float64(tpRPM)
+		if level == fanLevelAuto {
+			s.FanLevel.Push(math.NaN())
+		} else {
+			s.FanLevel.Push(float64(level))
+		}
+	}
+	s.Fan.Push(rpm)
+}
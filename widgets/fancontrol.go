@@ -0,0 +1,312 @@
+package widgets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maurodelazeri/harvey-gl/config"
+)
+
+// fanDevice takes the fan from the firmware on Acquire, sets its level, and
+// hands it back on Release. Refresh is called on every update, to tell the
+// device that the fan is still being looked after.
+type fanDevice interface {
+	Acquire() error
+	SetLevel(level int) error
+	Refresh() error
+	Release() error
+}
+
+// thinkpadWatchdog is the timeout in seconds after which thinkpad_acpi hands
+// the fan back to the firmware unless it is refreshed, e.g. because harvey
+// died without releasing it.
+const thinkpadWatchdog = 30
+
+type thinkpadDevice struct{}
+
+func (d thinkpadDevice) Acquire() error {
+	return d.Refresh()
+}
+
+func (thinkpadDevice) SetLevel(level int) error {
+	command := fmt.Sprintf("level %d", level)
+	if level >= MaxFanLevel {
+		command = "level full-speed"
+	}
+	return ioutil.WriteFile(thinkpadFan, []byte(command), 0644)
+}
+
+func (thinkpadDevice) Refresh() error {
+	return ioutil.WriteFile(thinkpadFan, []byte(fmt.Sprintf("watchdog %d", thinkpadWatchdog)), 0644)
+}
+
+func (thinkpadDevice) Release() error {
+	return ioutil.WriteFile(thinkpadFan, []byte("level auto"), 0644)
+}
+
+// pwmDevice drives a hwmon pwm file, mapping the levels evenly onto the duty
+// cycle. pwmN_enable is 1 for manual control; the firmware modes differ by
+// driver, so the one found on Acquire is restored on Release.
+type pwmDevice struct {
+	path   string
+	enable string
+}
+
+func (d *pwmDevice) Acquire() error {
+	buf, err := ioutil.ReadFile(d.path + "_enable")
+	if err != nil {
+		return err
+	}
+	d.enable = strings.TrimSpace(string(buf))
+	return ioutil.WriteFile(d.path+"_enable", []byte("1"), 0644)
+}
+
+func (d *pwmDevice) SetLevel(level int) error {
+	duty := level * 255 / MaxFanLevel
+	return ioutil.WriteFile(d.path, []byte(fmt.Sprint(duty)), 0644)
+}
+
+func (d *pwmDevice) Refresh() error {
+	return nil
+}
+
+func (d *pwmDevice) Release() error {
+	return ioutil.WriteFile(d.path+"_enable", []byte(d.enable), 0644)
+}
+
+// FanController drives the fan from the temperature along a curve, see
+// config.FanControl, unless overridden with a manual level. It is updated
+// from the stats and overridden from the keyboard, so every method locks it.
+type FanController struct {
+	Curve      []config.CurvePoint
+	Hysteresis float64
+	UpDwell    time.Duration
+	DownDwell  time.Duration
+
+	mu sync.Mutex
+
+	// level is the level the fan was last set to, manual is set while it is
+	// overridden.
+	level  int
+	manual bool
+
+	device   fanDevice
+	changed  time.Time
+	written  bool
+	released bool
+}
+
+func NewFanController(c config.FanControl) (*FanController, error) {
+	f := &FanController{}
+	if c.Hysteresis != nil {
+		f.Hysteresis = *c.Hysteresis
+	}
+	if f.Hysteresis < 0 {
+		return nil, fmt.Errorf("fan control: negative hysteresis %v", f.Hysteresis)
+	}
+
+	var err error
+	if f.UpDwell, err = time.ParseDuration(c.UpDwell); err != nil {
+		return nil, fmt.Errorf("fan control: %v", err)
+	}
+	if f.DownDwell, err = time.ParseDuration(c.DownDwell); err != nil {
+		return nil, fmt.Errorf("fan control: %v", err)
+	}
+
+	if len(c.Curve) == 0 {
+		return nil, fmt.Errorf("fan control: empty curve")
+	}
+	f.Curve = append([]config.CurvePoint{}, c.Curve...)
+	sort.Slice(f.Curve, func(i, j int) bool { return f.Curve[i].Temp < f.Curve[j].Temp })
+	for i, point := range f.Curve {
+		if point.Level < 0 || point.Level > MaxFanLevel {
+			return nil, fmt.Errorf("fan control: level %d out of 0-%d", point.Level, MaxFanLevel)
+		}
+		// Slowing down with hysteresis assumes the levels only go up.
+		if i > 0 && point.Level < f.Curve[i-1].Level {
+			return nil, fmt.Errorf("fan control: level %d at %v°C is below level %d at %v°C",
+				point.Level, point.Temp, f.Curve[i-1].Level, f.Curve[i-1].Temp)
+		}
+	}
+
+	if !readableThermal() {
+		return nil, fmt.Errorf("fan control: no temperature sensor can be read")
+	}
+
+	switch c.Device {
+	case "thinkpad":
+		f.device = thinkpadDevice{}
+	case "pwm":
+		if f.device = findPWM(c.PWM); f.device == nil {
+			return nil, fmt.Errorf("fan control: no pwm fan found")
+		}
+	case "":
+		if _, _, ok := readThinkpadFan(); ok {
+			f.device = thinkpadDevice{}
+		} else if f.device = findPWM(c.PWM); f.device == nil {
+			return nil, fmt.Errorf("fan control: no controllable fan found")
+		}
+	default:
+		return nil, fmt.Errorf("fan control: unknown device %q", c.Device)
+	}
+
+	if err := f.device.Acquire(); err != nil {
+		return nil, fmt.Errorf("fan control: %v", err)
+	}
+	return f, nil
+}
+
+func readableThermal() bool {
+	for _, temp := range readThermal() {
+		if !math.IsNaN(temp) {
+			return true
+		}
+	}
+	return false
+}
+
+func findPWM(path string) fanDevice {
+	if path != "" {
+		return &pwmDevice{path: path}
+	}
+	for _, fan := range FindFans() {
+		if fan.PWM != "" {
+			return &pwmDevice{path: fan.PWM}
+		}
+	}
+	return nil
+}
+
+// target is the level of the highest curve point at or below temp.
+func (f *FanController) target(temp float64) int {
+	level := f.Curve[0].Level
+	for _, point := range f.Curve {
+		if point.Temp > temp {
+			break
+		}
+		level = point.Level
+	}
+	return level
+}
+
+// Update moves the fan along the curve for the temperature at now. A level
+// is held for at least the dwell time before the fan speeds up or slows down,
+// and it only slows down once the temperature is Hysteresis below the point
+// that raised it, so that it doesn't flap around a point.
+//
+// An unknown temperature, NaN, isn't safe to run the fan slow at, so it runs
+// at full speed right away until the temperature is known again, overridden
+// or not.
+func (f *FanController) Update(temp float64, now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.released {
+		return
+	}
+	if err := f.device.Refresh(); err != nil {
+		log.Println("failed to refresh fan control:", err)
+	}
+	if math.IsNaN(temp) {
+		// Not even a manual level is safe without a temperature, so the
+		// override is dropped as well.
+		f.manual = false
+		f.set(MaxFanLevel, now)
+		return
+	}
+	if f.manual {
+		return
+	}
+
+	level := f.level
+	held := now.Sub(f.changed)
+	if up := f.target(temp); up > f.level && held >= f.UpDwell {
+		level = up
+	} else if down := f.target(temp + f.Hysteresis); down < f.level && held >= f.DownDwell {
+		level = down
+	}
+	f.set(level, now)
+}
+
+func (f *FanController) set(level int, now time.Time) {
+	if level == f.level && f.written {
+		return
+	}
+	if err := f.device.SetLevel(level); err != nil {
+		log.Println("failed to set fan level:", err)
+		return
+	}
+	f.level = level
+	f.changed = now
+	f.written = true
+}
+
+// Override sets the fan to a manual level until Auto is called.
+func (f *FanController) Override(level int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.override(level)
+}
+
+// Step overrides the fan with the level delta steps from the current one.
+func (f *FanController) Step(delta int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.override(f.level + delta)
+}
+
+func (f *FanController) override(level int) {
+	if f.released {
+		return
+	}
+	if level < 0 {
+		level = 0
+	} else if level > MaxFanLevel {
+		level = MaxFanLevel
+	}
+	f.manual = true
+	f.set(level, time.Now())
+}
+
+// Auto hands the fan back to the curve.
+func (f *FanController) Auto() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.manual = false
+}
+
+// Release hands the fan back to the firmware, e.g. on exit. The fan is left
+// alone from then on.
+func (f *FanController) Release() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.released = true
+	return f.device.Release()
+}
+
+// String is "curve L3" or "manual L3".
+func (f *FanController) String() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	mode := "curve"
+	if f.manual {
+		mode = "manual"
+	}
+	return fmt.Sprintf("%s L%d", mode, f.level)
+}
+
+// UpdateFanControl drives the fan from the latest temperature sample, NaN when
+// it couldn't be read.
+func (s *Stats) UpdateFanControl() {
+	if s.FanControl == nil {
+		return
+	}
+	s.FanControl.Update(s.Thermal.Latest(), time.Now())
+}
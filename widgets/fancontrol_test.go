@@ -0,0 +1,87 @@
+package widgets
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/maurodelazeri/harvey-gl/config"
+)
+
+// fakeFan records the levels it is set to.
+type fakeFan struct {
+	levels []int
+}
+
+func (f *fakeFan) Acquire() error { return nil }
+func (f *fakeFan) Refresh() error { return nil }
+func (f *fakeFan) Release() error { return nil }
+
+func (f *fakeFan) SetLevel(level int) error {
+	f.levels = append(f.levels, level)
+	return nil
+}
+
+func TestFanControllerUpdate(t *testing.T) {
+	type sample struct {
+		temp   float64
+		offset time.Duration
+	}
+	tests := []struct {
+		name       string
+		hysteresis float64
+		samples    []sample
+		want       []int
+	}{
+		{"below the curve", 3, []sample{{40, 0}}, []int{0}},
+		{"rising past a point", 3, []sample{{40, 0}, {55, 10 * time.Second}}, []int{0, 2}},
+		{"rising held by the dwell", 3, []sample{{55, 0}, {75, 2 * time.Second}}, []int{2}},
+		{"rising after the dwell", 3, []sample{{55, 0}, {75, 5 * time.Second}}, []int{2, 5}},
+		{"falling within the hysteresis", 3, []sample{{55, 0}, {48, time.Minute}}, []int{2}},
+		{"falling past the hysteresis", 3, []sample{{55, 0}, {46, time.Minute}}, []int{2, 0}},
+		{"falling held by the dwell", 3, []sample{{55, 0}, {40, 10 * time.Second}}, []int{2}},
+		{"falling without hysteresis", 0, []sample{{55, 0}, {49, time.Minute}}, []int{2, 0}},
+		{"at the point without hysteresis", 0, []sample{{55, 0}, {50, time.Minute}}, []int{2}},
+		{"unknown temperature", 3, []sample{{40, 0}, {math.NaN(), time.Second}}, []int{0, MaxFanLevel}},
+	}
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range tests {
+		fan := &fakeFan{}
+		f := &FanController{
+			Curve:      []config.CurvePoint{{Temp: 0, Level: 0}, {Temp: 50, Level: 2}, {Temp: 70, Level: 5}},
+			Hysteresis: test.hysteresis,
+			UpDwell:    5 * time.Second,
+			DownDwell:  30 * time.Second,
+			device:     fan,
+		}
+		for _, s := range test.samples {
+			f.Update(s.temp, start.Add(s.offset))
+		}
+		if !reflect.DeepEqual(fan.levels, test.want) {
+			t.Errorf("%s: set levels %v, want %v", test.name, fan.levels, test.want)
+		}
+	}
+}
+
+func TestNewFanControllerCurve(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve []config.CurvePoint
+		err   string
+	}{
+		{"empty", nil, "empty curve"},
+		{"decreasing", []config.CurvePoint{{Temp: 50, Level: 3}, {Temp: 70, Level: 2}}, "level 2 at 70°C is below level 3 at 50°C"},
+		{"decreasing unsorted", []config.CurvePoint{{Temp: 70, Level: 2}, {Temp: 50, Level: 3}}, "level 2 at 70°C is below level 3 at 50°C"},
+		{"above full speed", []config.CurvePoint{{Temp: 50, Level: MaxFanLevel + 1}}, "level 9 out of 0-8"},
+		{"negative", []config.CurvePoint{{Temp: 50, Level: -1}}, "level -1 out of 0-8"},
+	}
+	for _, test := range tests {
+		c := config.FanControl{Curve: test.curve, UpDwell: "5s", DownDwell: "30s"}
+		_, err := NewFanController(c)
+		if err == nil || err.Error() != "fan control: "+test.err {
+			t.Errorf("%s: got error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
@@ -1,69 +1,147 @@
 package widgets
 
 import (
+	"fmt"
 	"io/ioutil"
-	"regexp"
+	"math"
+	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/maurodelazeri/harvey-gl/config"
 	psutil_cpu "github.com/shirou/gopsutil/cpu"
-	psutil_mem "github.com/shirou/gopsutil/mem"
 )
 
 func NewStats() *Stats {
 	s := &Stats{
-		Updated:              make(chan bool),
-		FanGraphMaxCount:     60,
-		ThermalGraphMaxCount: 60,
-		MemoryGraphMaxCount:  60,
-		CpuGraphMaxCount:     60,
-
-		ThermalValueMin: 0xffff,
-		//FanValueMin:     0xffff,
-		MemoryValueMin: 0xffff,
-		CpuValueMin:    0xffff,
-
-		FanValueMin: 0,
-		FanValueMax: 10000,
+		Updated:  make(chan bool),
+		Thermal:  NewSeries("thermal", "C", 60),
+		Fan:      NewSeries("fan", "RPM", 60),
+		FanLevel: NewSeries("fan level", "", 60),
+		Memory:   NewSeries("memory", "%", 60),
+		Cpu:      NewSeries("cpu", "%", 60),
+		Battery:  NewSeries("battery", "%", 60),
+
+		BatteryHealth: NewSeries("battery health", "%", 60),
+
+		Swap:    NewSeries("swap", "%", 60),
+		SwapIn:  NewSeries("swap in", "KiB/s", 60),
+		SwapOut: NewSeries("swap out", "KiB/s", 60),
+		swapIn:  NewCounter(time.Minute),
+		swapOut: NewCounter(time.Minute),
+
+		Disk:   config.DefaultDisk(),
+		System: NewSystem(),
+
+		AC:     NewSeries("ac", "", 60),
+		Events: NewEvents(20),
+
+		estimator: NewEstimator(time.Minute * 5),
+	}
+
+	for i := 0; i < runtime.NumCPU(); i++ {
+		s.Cores = append(s.Cores, NewSeries(fmt.Sprintf("cpu%d", i), "%", 60))
+	}
+
+	for _, path := range thermalSensors {
+		name := strings.TrimSuffix(filepath.Base(path), "_input")
+		s.Sensors = append(s.Sensors, NewSeries(name, "C", 60))
 	}
 	return s
 }
 
+// Stats is locked while it is updated, whoever reads it from another
+// goroutine, e.g. to render it, locks it as well.
 type Stats struct {
+	sync.Mutex
+
 	Updated chan bool
 
-	ThermalValue         int
-	ThermalValueMax      int
-	ThermalValueMin      int
-	ThermalGraph         []int
-	ThermalGraphMaxCount int
-
-	FanLevel         int
-	FanValue         int
-	FanValueMax      int
-	FanValueMin      int
-	FanGraph         []int
-	FanGraphMaxCount int
-
-	MemoryValue         float64
-	MemoryValueMax      float64
-	MemoryValueMin      float64
-	MemoryGraph         []float64
-	MemoryGraphMaxCount int
-
-	CpuValue         float64
-	CpuValueMax      float64
-	CpuValueMin      float64
-	CpuGraph         []float64
-	CpuGraphMaxCount int
+	Thermal  *Series
+	Fan      *Series
+	FanLevel *Series
+	Memory   *Series
+	Cpu      *Series
+	Battery  *Series
+
+	// Batteries holds every battery found, Pack their combined state. Pack
+	// is nil on machines without a battery.
+	Batteries     []BatteryStatus
+	Pack          *BatteryStatus
+	BatteryHealth *Series
+
+	// Estimate is the time to empty or full of Pack, smoothed over the
+	// recent discharge history by estimator.
+	Estimate  Estimate
+	estimator *Estimator
+
+	// Supplies holds every power supply, Online whether any external one
+	// powers the machine and AC the same as 1 or 0. Plugging and unplugging
+	// are recorded in Events.
+	Supplies []PowerSupply
+	Online   bool
+	AC       *Series
+	Events   *Events
+
+	// MemoryInfo is the breakdown of the memory in use, Memory its used
+	// share. SwapIn and SwapOut are the swap traffic.
+	MemoryInfo MemoryInfo
+	Swap       *Series
+	SwapIn     *Series
+	SwapOut    *Series
+	swapIn     *Counter
+	swapOut    *Counter
+
+	// System holds the load, process counts and uptime.
+	System *System
+
+	// Processes holds every process by PID.
+	Processes map[int]*Process
+
+	// Pressure holds the stall information of the cpu, memory and io.
+	Pressure []*Pressure
+
+	// Disks holds the I/O of the devices and Mounts the usage of the
+	// filesystems selected by Disk.
+	Disk   config.Disk
+	Disks  []*Disk
+	Mounts []*Mount
+
+	// Interfaces holds the state of every network interface.
+	Interfaces []Interface
+
+	// Fans holds every hwmon fan, discovered once on the first update. Fan
+	// is the fastest of them, FanLevel the thinkpad_acpi level on ThinkPads.
+	Fans      []*FanSensor
+	fansFound bool
+
+	// FanControl drives the fan along a temperature curve, nil unless
+	// enabled.
+	FanControl *FanController
+
+	// Cores and Sensors hold one series per CPU core and per thermal sensor.
+	Cores   []*Series
+	Sensors []*Series
 }
 
 func (s *Stats) Run() {
+	s.Lock()
 	s.UpdateMemory()
 	s.UpdateCPU()
 	s.UpdateThermal()
 	s.UpdateFan()
+	s.UpdateFanControl()
+	s.UpdateBattery()
+	s.UpdateInterfaces()
+	s.UpdateDisks()
+	s.UpdateMounts()
+	s.UpdatePressure()
+	s.UpdateSystem()
+	s.UpdateProcesses()
+	s.Unlock()
 	s.Updated <- true
 
 	five := time.NewTicker(time.Second * 5)
@@ -71,108 +149,108 @@ func (s *Stats) Run() {
 	for {
 		select {
 		case <-five.C:
+			s.Lock()
 			s.UpdateCPU()
 			s.UpdateThermal()
 			s.UpdateFan()
+			s.UpdateFanControl()
+			s.UpdateInterfaces()
+			s.UpdateDisks()
+			s.UpdatePressure()
+			s.UpdateSystem()
+			s.UpdateProcesses()
+			s.Unlock()
 			break
 		case <-ten.C:
+			s.Lock()
 			s.UpdateMemory()
+			s.UpdateBattery()
+			s.UpdateMounts()
+			s.Unlock()
 			break
 		}
 		s.Updated <- true
 	}
 }
 
-var fanRegexp *regexp.Regexp = regexp.MustCompile("speed:\t\t(\\d+)\nlevel:\t\t(.+)")
-
-func (s *Stats) UpdateFan() {
-	var rpm int
-	var level int
-	var file string = "/proc/acpi/ibm/fan"
-
-	if buf, err := ioutil.ReadFile(file); err == nil {
-		m := fanRegexp.FindStringSubmatch(string(buf))
-		if len(m) == 3 {
-			rpm, _ = strconv.Atoi(m[1])
-			if m[2] == "disengaged" {
-				level = 8
-			} else {
-				level, _ = strconv.Atoi(m[2])
-			}
-		}
-	}
-
-	s.FanLevel = level
-	s.FanValue = rpm
-
-	if s.FanValue > s.FanValueMax {
-		s.FanValue = s.FanValueMax
-	}
-
-	if s.FanValue < s.FanValueMin {
-		s.FanValue = s.FanValueMin
-	}
-
-	if len(s.FanGraph) >= s.FanGraphMaxCount {
-		s.FanGraph = append(s.FanGraph[1:], s.FanValue)
-	} else {
-		s.FanGraph = append(s.FanGraph, s.FanValue)
-	}
+var thermalSensors []string = []string{
+	"/sys/devices/platform/coretemp.0/hwmon/hwmon0/temp1_input",
+	"/sys/devices/platform/coretemp.0/hwmon/hwmon0/temp2_input",
+	"/sys/devices/platform/coretemp.0/hwmon/hwmon0/temp3_input",
 }
 
-func (s *Stats) UpdateThermal() {
-	var max uint64 = 0
-
-	sensors := []string{
-		"/sys/devices/platform/coretemp.0/hwmon/hwmon0/temp1_input",
-		"/sys/devices/platform/coretemp.0/hwmon/hwmon0/temp2_input",
-		"/sys/devices/platform/coretemp.0/hwmon/hwmon0/temp3_input",
-	}
-
-	for _, path := range sensors {
+// readThermal reads every thermal sensor in °C, NaN for those that can't be
+// read.
+func readThermal() []float64 {
+	temps := make([]float64, len(thermalSensors))
+	for i, path := range thermalSensors {
+		temps[i] = math.NaN()
 		if buf, err := ioutil.ReadFile(path); err == nil {
 			str := strings.Replace(string(buf), "\n", "", -1)
 			value, err := strconv.ParseUint(str, 10, 64)
-			if err == nil && value > max {
-				max = value
+			if err == nil {
+				temps[i] = float64(value / 1000)
 			}
 		}
 	}
+	return temps
+}
 
-	s.ThermalValue = int(max / 1000)
-
-	if s.ThermalValue > s.ThermalValueMax {
-		s.ThermalValueMax = s.ThermalValue
+// UpdateThermal updates every sensor, and Thermal with the hottest. Thermal
+// is NaN when none of the sensors can be read.
+func (s *Stats) UpdateThermal() {
+	max := math.NaN()
+	for i, temp := range readThermal() {
+		s.Sensors[i].Push(temp)
+		if math.IsNaN(max) || temp > max {
+			max = temp
+		}
 	}
 
-	if s.ThermalValue < s.ThermalValueMin {
-		s.ThermalValueMin = s.ThermalValue
-	}
+	s.Thermal.Push(max)
+}
 
-	if len(s.ThermalGraph) >= s.ThermalGraphMaxCount {
-		s.ThermalGraph = append(s.ThermalGraph[1:], s.ThermalValue)
-	} else {
-		s.ThermalGraph = append(s.ThermalGraph, s.ThermalValue)
+func (s *Stats) UpdateBattery() {
+	supplies, err := ReadPowerSupplies()
+	if err != nil {
+		return
 	}
-}
 
-func (s *Stats) UpdateMemory() {
-	v, _ := psutil_mem.VirtualMemory()
-	s.MemoryValue = v.UsedPercent
+	s.Supplies = supplies
+	s.Batteries = Batteries(supplies)
+	s.Pack = CombineBatteries(s.Batteries)
+	if s.Pack != nil {
+		s.Battery.Push(s.Pack.Percent)
+		s.BatteryHealth.Push(s.Pack.Health)
+	}
 
-	if s.MemoryValue > s.MemoryValueMax {
-		s.MemoryValueMax = s.MemoryValue
+	online := OnlineAC(supplies, s.Pack)
+	if len(s.AC.Values) > 0 && online != s.Online {
+		if online {
+			s.Events.Add("plugged", "AC")
+		} else {
+			s.Events.Add("unplugged", "BAT")
+		}
+	}
+	s.Online = online
+	if online {
+		s.AC.Push(1)
+	} else {
+		s.AC.Push(0)
 	}
 
-	if s.MemoryValue < s.MemoryValueMin {
-		s.MemoryValueMin = s.MemoryValue
+	s.Estimate = s.estimator.Update(s.Pack, time.Now())
+	if s.Estimate.Valid && s.Pack != nil {
+		s.Pack.Remaining = HoursMinutes(s.Estimate.Seconds)
 	}
+}
 
-	if len(s.MemoryGraph) >= s.MemoryGraphMaxCount {
-		s.MemoryGraph = append(s.MemoryGraph[1:], s.MemoryValue)
-	} else {
-		s.MemoryGraph = append(s.MemoryGraph, s.MemoryValue)
+func (s *Stats) UpdateInterfaces() {
+	interfaces, err := ReadInterfaces()
+	if err != nil {
+		return
 	}
+	s.Interfaces = interfaces
 }
 
 func (s *Stats) UpdateCPU() {
@@ -182,19 +260,15 @@ func (s *Stats) UpdateCPU() {
 		return
 	}
 
-	s.CpuValue = percent[0]
+	s.Cpu.Push(percent[0])
 
-	if s.CpuValue > s.CpuValueMax {
-		s.CpuValueMax = s.CpuValue
-	}
-
-	if s.CpuValue < s.CpuValueMin {
-		s.CpuValueMin = s.CpuValue
+	cores, err := psutil_cpu.Percent(0, true)
+	if err != nil {
+		return
 	}
-
-	if len(s.CpuGraph) >= s.CpuGraphMaxCount {
-		s.CpuGraph = append(s.CpuGraph[1:], s.CpuValue)
-	} else {
-		s.CpuGraph = append(s.CpuGraph, s.CpuValue)
+	for i, value := range cores {
+		if i < len(s.Cores) {
+			s.Cores[i].Push(value)
+		}
 	}
 }
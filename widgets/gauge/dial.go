@@ -0,0 +1,101 @@
+package gauge
+
+import (
+	"image"
+	"math"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// The dial sweeps clockwise from the bottom left to the bottom right, leaving
+// the bottom quarter free for the value label.
+const (
+	dialStart = math.Pi * 0.75
+	dialSweep = math.Pi * 1.5
+)
+
+// Dial is a radial gauge drawn as an arc with threshold bands along its
+// outside, tick marks and a needle.
+type Dial struct {
+	Gauge
+	Texture *texture.Texture
+
+	Thickness float64
+}
+
+func NewDial(program *shader.Program, x, y, size float64, series *widgets.Series, min, max float64, format string) *Dial {
+	d := &Dial{
+		Gauge:     newGauge(series, min, max, format),
+		Texture:   &texture.Texture{X: x, Y: y, Width: size, Height: size},
+		Thickness: 6,
+	}
+	d.Texture.Setup(program)
+	return d
+}
+
+func (d *Dial) angle(value float64) float64 {
+	return dialStart + (d.ratio(value) * dialSweep)
+}
+
+func (d *Dial) arc(gc *draw2dimg.GraphicContext, cx, cy, radius, from, to float64) {
+	gc.MoveTo(cx+(math.Cos(from)*radius), cy+(math.Sin(from)*radius))
+	gc.ArcTo(cx, cy, radius, radius, from, to-from)
+	gc.Stroke()
+}
+
+func (d *Dial) Render() {
+	size := d.Texture.Width
+	data := image.NewRGBA(image.Rect(0, 0, int(size), int(d.Texture.Height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(d.Background)
+	draw2dkit.Rectangle(gc, 0, 0, size, d.Texture.Height)
+	gc.Fill()
+
+	cx, cy := size/2, size/2
+	radius := (size / 2) - d.Thickness - 2
+
+	gc.SetLineWidth(d.Thickness)
+	gc.SetStrokeColor(d.TrackColor)
+	d.arc(gc, cx, cy, radius, dialStart, dialStart+dialSweep)
+
+	gc.SetLineWidth(2)
+	for _, band := range d.Bands {
+		gc.SetStrokeColor(band.Color)
+		d.arc(gc, cx, cy, radius+d.Thickness/2+1, d.angle(band.From), d.angle(band.To))
+	}
+
+	gc.SetLineWidth(d.Thickness)
+	gc.SetStrokeColor(d.valueColor())
+	d.arc(gc, cx, cy, radius, dialStart, d.angle(d.value()))
+
+	gc.SetLineWidth(1)
+	gc.SetStrokeColor(d.Color)
+	if d.Ticks > 1 {
+		for i := 0; i < d.Ticks; i++ {
+			a := dialStart + (float64(i) / float64(d.Ticks-1) * dialSweep)
+			inner := radius - d.Thickness
+			gc.MoveTo(cx+(math.Cos(a)*inner), cy+(math.Sin(a)*inner))
+			gc.LineTo(cx+(math.Cos(a)*(inner+3)), cy+(math.Sin(a)*(inner+3)))
+		}
+		gc.Stroke()
+	}
+
+	a := d.angle(d.value())
+	needle := radius - d.Thickness - 4
+	gc.SetLineWidth(2)
+	gc.MoveTo(cx, cy)
+	gc.LineTo(cx+(math.Cos(a)*needle), cy+(math.Sin(a)*needle))
+	gc.Stroke()
+
+	label := d.Label()
+	font.DrawString(data, int(cx)-((len(label)*font.Width)/2), int(cy+(radius/2)), label, d.Color)
+
+	d.Texture.Write(&data.Pix)
+}
@@ -0,0 +1,93 @@
+package gauge
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/maurodelazeri/harvey-gl/widgets"
+)
+
+// Band colors the part of the scale between From and To, e.g. a red band
+// for a battery below 10%.
+type Band struct {
+	From  float64
+	To    float64
+	Color color.RGBA
+}
+
+// Gauge holds what dials and meters have in common: the series they are bound
+// to, the scale and how it is labelled.
+type Gauge struct {
+	Series *widgets.Series
+	Min    float64
+	Max    float64
+
+	Ticks  int
+	Bands  []Band
+	Format string
+
+	// Unknown is the label shown while the latest sample is unknown, NaN.
+	Unknown string
+
+	Color      color.RGBA
+	TrackColor color.RGBA
+	Background color.RGBA
+}
+
+func newGauge(series *widgets.Series, min, max float64, format string) Gauge {
+	return Gauge{
+		Series:     series,
+		Min:        min,
+		Max:        max,
+		Ticks:      5,
+		Format:     format,
+		Unknown:    "?",
+		Color:      color.RGBA{0x99, 0x99, 0x99, 0xff},
+		TrackColor: color.RGBA{0x44, 0x44, 0x44, 0xff},
+		Background: color.RGBA{0x33, 0x33, 0x33, 0xff},
+	}
+}
+
+// Band adds a threshold band to the scale.
+func (g *Gauge) Band(from, to float64, clr color.RGBA) {
+	g.Bands = append(g.Bands, Band{From: from, To: to, Color: clr})
+}
+
+// value is the latest sample, the gauge is empty while it is unknown.
+func (g *Gauge) value() float64 {
+	return g.Series.Latest()
+}
+
+func (g *Gauge) Label() string {
+	value := g.value()
+	if math.IsNaN(value) {
+		return g.Unknown
+	}
+	return fmt.Sprintf(g.Format, value)
+}
+
+// ratio maps value onto 0..1 along the scale, clamped at both ends.
+func (g *Gauge) ratio(value float64) float64 {
+	if g.Max <= g.Min {
+		return 0
+	}
+	ratio := (value - g.Min) / (g.Max - g.Min)
+	if ratio < 0 || math.IsNaN(ratio) {
+		return 0
+	} else if ratio > 1 {
+		return 1
+	}
+	return ratio
+}
+
+// valueColor is the color of the band the current value is in, or Color.
+func (g *Gauge) valueColor() color.RGBA {
+	value := g.value()
+	for _, band := range g.Bands {
+		if value >= band.From && value <= band.To {
+			return band.Color
+		}
+	}
+	return g.Color
+}
@@ -0,0 +1,115 @@
+package gauge
+
+import (
+	"image"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Meter is a linear bar gauge. Horizontal meters fill left to right with the
+// label on the right, vertical meters fill bottom to top with the label
+// underneath.
+type Meter struct {
+	Gauge
+	Texture *texture.Texture
+
+	Vertical bool
+}
+
+func NewMeter(program *shader.Program, x, y, width, height float64, series *widgets.Series, min, max float64, format string) *Meter {
+	m := &Meter{
+		Gauge:    newGauge(series, min, max, format),
+		Texture:  &texture.Texture{X: x, Y: y, Width: width, Height: height},
+		Vertical: height > width,
+	}
+	m.Texture.Setup(program)
+	return m
+}
+
+// track returns the rectangle the bar is drawn in, leaving room for the
+// label, the bands and the ticks.
+func (m *Meter) track() (x1, y1, x2, y2 float64) {
+	width, height := m.Texture.Width, m.Texture.Height
+	if m.Vertical {
+		return 4, 2, width - 4, height - font.Height - 2
+	}
+	return 2, 4, width - float64((len(m.Label())+1)*font.Width), height - 4
+}
+
+// position maps value onto the track, along x for horizontal meters and
+// along y for vertical ones.
+func (m *Meter) position(value float64) float64 {
+	x1, y1, x2, y2 := m.track()
+	if m.Vertical {
+		return y2 - (m.ratio(value) * (y2 - y1))
+	}
+	return x1 + (m.ratio(value) * (x2 - x1))
+}
+
+func (m *Meter) Render() {
+	width, height := m.Texture.Width, m.Texture.Height
+	data := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(m.Background)
+	draw2dkit.Rectangle(gc, 0, 0, width, height)
+	gc.Fill()
+
+	x1, y1, x2, y2 := m.track()
+
+	gc.SetFillColor(m.TrackColor)
+	draw2dkit.Rectangle(gc, x1, y1, x2, y2)
+	gc.Fill()
+
+	gc.SetFillColor(m.valueColor())
+	if m.Vertical {
+		draw2dkit.Rectangle(gc, x1, m.position(m.value()), x2, y2)
+	} else {
+		draw2dkit.Rectangle(gc, x1, y1, m.position(m.value()), y2)
+	}
+	gc.Fill()
+
+	// Bands run along the top or left edge, ticks along the opposite one.
+	for _, band := range m.Bands {
+		gc.SetFillColor(band.Color)
+		from, to := m.position(band.From), m.position(band.To)
+		if m.Vertical {
+			draw2dkit.Rectangle(gc, 0, to, x1-1, from)
+		} else {
+			draw2dkit.Rectangle(gc, from, 0, to, y1-1)
+		}
+		gc.Fill()
+	}
+
+	gc.SetStrokeColor(m.Color)
+	gc.SetLineWidth(1)
+	if m.Ticks > 1 {
+		for i := 0; i < m.Ticks; i++ {
+			value := m.Min + (float64(i) / float64(m.Ticks-1) * (m.Max - m.Min))
+			p := m.position(value)
+			if m.Vertical {
+				gc.MoveTo(x2, p)
+				gc.LineTo(width, p)
+			} else {
+				gc.MoveTo(p, y2)
+				gc.LineTo(p, height)
+			}
+		}
+		gc.Stroke()
+	}
+
+	label := m.Label()
+	if m.Vertical {
+		font.DrawString(data, int(width/2)-((len(label)*font.Width)/2), int(y2)+2, label, m.Color)
+	} else {
+		font.DrawString(data, int(x2)+font.Width, int((height-font.Height)/2), label, m.Color)
+	}
+
+	m.Texture.Write(&data.Pix)
+}
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// tickIntervals are the candidate spacings for time ticks, the first one that
+// leaves room for its labels is used.
+var tickIntervals = []time.Duration{
+	time.Minute,
+	time.Minute * 5,
+	time.Minute * 15,
+	time.Minute * 30,
+	time.Hour,
+	time.Hour * 6,
+}
+
+func axisWidth(format string, min, max float64) int {
+	width := len(fmt.Sprintf(format, min))
+	if w := len(fmt.Sprintf(format, max)); w > width {
+		width = w
+	}
+	return (width + 1) * font.Width
+}
+
+// levels returns the fractions of the plot height that get a gridline and a
+// Y axis label. The mid level is dropped when the labels wouldn't fit.
+func (p plot) levels() []float64 {
+	if p.Height() >= font.Height*3 {
+		return []float64{0, 0.5, 1}
+	}
+	return []float64{0, 1}
+}
+
+func (g *Graph) drawGrid(gc *draw2dimg.GraphicContext, area plot) {
+	gc.SetStrokeColor(g.GridColor)
+	gc.SetLineWidth(1.0)
+	for _, level := range area.levels() {
+		y := area.Bottom - float64(int(level*area.Height()))
+		gc.MoveTo(area.Left, y)
+		gc.LineTo(area.Right, y)
+	}
+	gc.Stroke()
+}
+
+// drawAxis labels the levels of the plot. Left hand labels are right aligned
+// against the plot and kept right of x, right hand labels start next to the
+// plot and are kept left of x.
+func (g *Graph) drawAxis(data *image.RGBA, area plot, x, min, max float64, clr color.RGBA, right bool) {
+	for _, level := range area.levels() {
+		text := fmt.Sprintf(g.AxisFormat, min+(level*(max-min)))
+
+		ty := int(area.Bottom-(level*area.Height())) - (font.Height / 2)
+		if ty < int(area.Top) {
+			ty = int(area.Top)
+		} else if ty > int(area.Bottom)-font.Height {
+			ty = int(area.Bottom) - font.Height
+		}
+		var tx int
+		if right {
+			tx = int(area.Right) + font.Width
+			if limit := int(x) - (len(text) * font.Width); tx > limit {
+				tx = limit
+			}
+		} else {
+			tx = int(area.Left) - ((len(text) + 1) * font.Width)
+			if tx < int(x) {
+				tx = int(x)
+			}
+		}
+		font.DrawString(data, tx, ty, text, clr)
+	}
+}
+
+// drawTimeTicks marks whole minutes or hours back from the newest sample.
+// Positions come from the sample timestamps, so gaps in the series show up as
+// unevenly spaced ticks.
+func (g *Graph) drawTimeTicks(gc *draw2dimg.GraphicContext, data *image.RGBA, area plot, points []point, times []time.Time) {
+	if len(times) > len(points) {
+		times = times[len(times)-len(points):]
+	} else if len(points) > len(times) {
+		points = points[len(points)-len(times):]
+	}
+	if len(times) < 2 {
+		return
+	}
+
+	newest := times[len(times)-1]
+	span := newest.Sub(times[0])
+	if span <= 0 {
+		return
+	}
+	perSecond := (points[len(points)-1].X - points[0].X) / span.Seconds()
+
+	interval := tickIntervals[len(tickIntervals)-1]
+	for _, candidate := range tickIntervals {
+		if candidate.Seconds()*perSecond >= float64(font.Width*6) {
+			interval = candidate
+			break
+		}
+	}
+
+	gc.SetStrokeColor(g.GridColor)
+	gc.SetLineWidth(1.0)
+	i := len(times) - 1
+	for age := interval; age <= span; age += interval {
+		for i > 0 && newest.Sub(times[i]) < age {
+			i--
+		}
+		px := points[i].X
+		gc.MoveTo(px, area.Top)
+		gc.LineTo(px, area.Bottom)
+
+		text := formatAge(age)
+		font.DrawString(data, int(px)-((len(text)*font.Width)/2), int(area.Bottom), text, g.Color)
+	}
+	gc.Stroke()
+}
+
+func formatAge(age time.Duration) string {
+	if age%time.Hour == 0 {
+		return fmt.Sprintf("-%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("-%dm", int(age.Minutes()))
+}
+
+// drawMarker highlights the newest sample and extends its level to the right
+// edge of the plot, next to the value label.
+func (g *Graph) drawMarker(gc *draw2dimg.GraphicContext, last point, area plot) {
+	gc.SetStrokeColor(g.Color)
+	gc.SetLineWidth(1.0)
+	gc.MoveTo(last.X, last.Y)
+	gc.LineTo(area.Right, last.Y)
+	gc.Stroke()
+
+	gc.SetFillColor(g.Color)
+	draw2dkit.Circle(gc, last.X, last.Y, 2)
+	gc.Fill()
+}
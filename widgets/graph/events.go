@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"image"
+	"time"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Marked marks the events on the graph, e.g. AC plug and unplug.
+func (g *Graph) Marked(events *widgets.Events) *Graph {
+	g.Events = events
+	return g
+}
+
+// drawEvents draws a vertical line with the event label at the position of
+// every event within the visible samples, interpolating between the
+// timestamps of the samples around it.
+func (g *Graph) drawEvents(gc *draw2dimg.GraphicContext, data *image.RGBA, area plot, points []point, times []time.Time) {
+	if len(times) > len(points) {
+		times = times[len(times)-len(points):]
+	} else if len(points) > len(times) {
+		points = points[len(points)-len(times):]
+	}
+	if len(times) < 2 {
+		return
+	}
+
+	gc.SetStrokeColor(g.EventColor)
+	gc.SetLineWidth(1.0)
+	i := 0
+	for _, event := range g.Events.Since(times[0]) {
+		if event.Time.After(times[len(times)-1]) {
+			break
+		}
+		for i < len(times)-2 && times[i+1].Before(event.Time) {
+			i++
+		}
+
+		px := points[i].X
+		if span := times[i+1].Sub(times[i]); span > 0 {
+			ratio := event.Time.Sub(times[i]).Seconds() / span.Seconds()
+			px += ratio * (points[i+1].X - points[i].X)
+		}
+		gc.MoveTo(px, area.Top)
+		gc.LineTo(px, area.Bottom)
+		font.DrawString(data, int(px)+2, int(area.Top), event.Label, g.EventColor)
+	}
+	gc.Stroke()
+}
@@ -0,0 +1,265 @@
+package graph
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Graph draws the history of a series with its current value as a label on
+// the right, or with a legend when other series are overlaid. A graph doesn't
+// own a texture; it is drawn into the image of whatever widget places it, see
+// Panel.
+type Graph struct {
+	Series *widgets.Series
+	Range
+
+	Format    string
+	Width     float64
+	Height    float64
+	StepWidth int
+
+	Style     Style
+	LineWidth float64
+	Color     color.RGBA
+	FillColor color.RGBA
+
+	Axes       bool
+	AxisFormat string
+	Grid       bool
+	GridColor  color.RGBA
+	TimeTicks  bool
+	Marker     bool
+
+	Overlays []*Overlay
+	Legend   bool
+
+	Events     *widgets.Events
+	EventColor color.RGBA
+}
+
+func New(series *widgets.Series, format string, width, height float64) *Graph {
+	return &Graph{
+		Series:    series,
+		Range:     Range{Scale: ScaleAllTime, Padding: 0.05},
+		Format:    format,
+		Width:     width,
+		Height:    height,
+		StepWidth: 8,
+		Style:     StyleStep,
+		LineWidth: 1.0,
+		Color:     color.RGBA{0x66, 0x66, 0x66, 0xff},
+		FillColor: color.RGBA{0x66, 0x66, 0x66, 0x99},
+
+		AxisFormat: "%.0f",
+		GridColor:  color.RGBA{0x44, 0x44, 0x44, 0xff},
+		EventColor: color.RGBA{0x33, 0x99, 0x66, 0xff},
+	}
+}
+
+// Fixed switches the graph to a fixed range.
+func (g *Graph) Fixed(min, max float64) *Graph {
+	g.Scale = ScaleFixed
+	g.Min = min
+	g.Max = max
+	return g
+}
+
+// Scaled switches the graph to the given scaling mode.
+func (g *Graph) Scaled(scale Scale) *Graph {
+	g.Scale = scale
+	return g
+}
+
+// Styled switches the graph to the given drawing style.
+func (g *Graph) Styled(style Style) *Graph {
+	g.Style = style
+	return g
+}
+
+// Annotated turns on the Y axis labels, gridlines, time ticks and the last
+// value marker.
+func (g *Graph) Annotated() *Graph {
+	g.Axes = true
+	g.Grid = true
+	g.TimeTicks = true
+	g.Marker = true
+	return g
+}
+
+func (g *Graph) Label() string {
+	return fmt.Sprintf(g.Format, g.Series.Value)
+}
+
+// plot is the part of the graph the samples are drawn into, excluding the
+// label and the axes.
+type plot struct {
+	Left   float64
+	Top    float64
+	Right  float64
+	Bottom float64
+}
+
+func (p plot) Height() float64 {
+	return p.Bottom - p.Top
+}
+
+// visible returns how many samples fit the plot.
+func (p plot) visible(step int) int {
+	return int(p.Right-p.Left) / step
+}
+
+// scaled maps value to a y coordinate inside the plot, unknown values stay
+// NaN.
+func (p plot) scaled(value, min, max float64) float64 {
+	if math.IsNaN(value) {
+		return value
+	}
+	ratio := (value - min) / (max - min)
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+	return p.Bottom - float64(int(ratio*p.Height()))
+}
+
+// plot lays out the graph for the given number of visible samples, which
+// decides the width of the axis labels.
+func (g *Graph) plot(x, y float64, visible int) plot {
+	p := plot{
+		Left:   x,
+		Top:    y,
+		Right:  x + g.Width,
+		Bottom: y + g.Height,
+	}
+	if g.Legend {
+		p.Top += font.Height
+	} else {
+		p.Right -= float64((len(g.Label()) + 2) * font.Width)
+	}
+	if g.Axes {
+		min, max := g.Bounds(g.Series, visible)
+		p.Left += float64(axisWidth(g.AxisFormat, min, max))
+		if o := g.rightAxis(); o != nil {
+			min, max = o.Bounds(o.Series, visible)
+			p.Right -= float64(axisWidth(g.AxisFormat, min, max))
+		}
+	}
+	if g.TimeTicks {
+		p.Bottom -= font.Height
+	}
+	return p
+}
+
+func (g *Graph) points(series *widgets.Series, area plot, maxItems int, min, max float64) []point {
+	values := series.Last(maxItems)
+	points := make([]point, len(values))
+	for i, value := range values {
+		points[i] = point{X: area.Left + float64(i*g.StepWidth), Y: area.scaled(value, min, max)}
+	}
+	return points
+}
+
+// Draw draws the graph with its top left corner at x, y.
+func (g *Graph) Draw(gc *draw2dimg.GraphicContext, data *image.RGBA, x, y float64) {
+	// The visible window depends on the axis widths and the other way around,
+	// so lay out once for the widest possible window and again for the result.
+	area := g.plot(x, y, int(g.Width)/g.StepWidth)
+	area = g.plot(x, y, area.visible(g.StepWidth))
+	maxItems := area.visible(g.StepWidth)
+
+	min, max := g.Bounds(g.Series, maxItems)
+	points := g.points(g.Series, area, maxItems, min, max)
+
+	if g.Grid {
+		g.drawGrid(gc, area)
+	}
+	if g.Axes {
+		g.drawAxis(data, area, x, min, max, g.Color, false)
+		if o := g.rightAxis(); o != nil {
+			omin, omax := o.Bounds(o.Series, maxItems)
+			g.drawAxis(data, area, x+g.Width, omin, omax, o.Color, true)
+		}
+	}
+	if g.TimeTicks {
+		g.drawTimeTicks(gc, data, area, points, g.Series.LastTimes(maxItems))
+	}
+	if g.Events != nil {
+		g.drawEvents(gc, data, area, points, g.Series.LastTimes(maxItems))
+	}
+
+	g.drawSeries(gc, data, points, area, g.Color, g.FillColor)
+	for _, o := range g.Overlays {
+		omin, omax := min, max
+		if o.Own {
+			omin, omax = o.Bounds(o.Series, maxItems)
+		}
+		g.drawSeries(gc, data, g.points(o.Series, area, maxItems, omin, omax), area, o.Color, o.FillColor)
+	}
+
+	if g.Marker && len(points) > 0 && !math.IsNaN(points[len(points)-1].Y) {
+		g.drawMarker(gc, points[len(points)-1], area)
+	}
+
+	if g.Legend {
+		g.drawLegend(data, x, y)
+	} else {
+		label := g.Label()
+		lx := int(x+g.Width) - ((len(label) + 1) * font.Width)
+		ly := int(y + ((g.Height - font.Height) / 2))
+		font.DrawString(data, lx, ly, label, g.Color)
+	}
+}
+
+// drawSeries draws the points in the graph style, leaving a gap at unknown
+// samples.
+func (g *Graph) drawSeries(gc *draw2dimg.GraphicContext, data *image.RGBA, points []point, area plot, stroke, fill color.RGBA) {
+	gc.SetStrokeColor(stroke)
+	gc.SetLineWidth(g.LineWidth)
+
+	for _, run := range runs(points) {
+		g.drawRun(gc, data, run, area, stroke, fill)
+	}
+}
+
+// runs splits points into the runs of known samples between unknown ones.
+func runs(points []point) [][]point {
+	var runs [][]point
+	start := 0
+	for i, p := range points {
+		if math.IsNaN(p.Y) {
+			if i > start {
+				runs = append(runs, points[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(points) {
+		runs = append(runs, points[start:])
+	}
+	return runs
+}
+
+func (g *Graph) drawRun(gc *draw2dimg.GraphicContext, data *image.RGBA, points []point, area plot, stroke, fill color.RGBA) {
+	step := float64(g.StepWidth)
+
+	switch g.Style {
+	case StyleLine:
+		g.drawLine(gc, points)
+	case StyleArea:
+		g.drawArea(gc, data, points, area.Top, area.Bottom, fill)
+	case StyleBars:
+		g.drawBars(gc, points, step, area.Bottom, fill)
+	case StyleDots:
+		g.drawDots(gc, points, stroke)
+	default:
+		g.drawStep(gc, points, step)
+	}
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/maurodelazeri/harvey-gl/widgets"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Overlay is an additional series drawn on top of a graph in its own color,
+// using the graph's style.
+type Overlay struct {
+	Series    *widgets.Series
+	Format    string
+	Color     color.RGBA
+	FillColor color.RGBA
+
+	// Own scales the overlay to its own Range, labelled on a right hand axis,
+	// instead of sharing the range of the graph.
+	Own bool
+	Range
+}
+
+func (o *Overlay) Label() string {
+	return fmt.Sprintf(o.Format, o.Series.Value)
+}
+
+// Overlay adds series on top of the graph and turns the legend on.
+func (g *Graph) Overlay(series *widgets.Series, format string, clr color.RGBA, own bool) *Graph {
+	g.Overlays = append(g.Overlays, &Overlay{
+		Series:    series,
+		Format:    format,
+		Color:     clr,
+		FillColor: color.RGBA{clr.R, clr.G, clr.B, 0x99},
+		Own:       own,
+		Range:     Range{Scale: ScaleAllTime, Padding: 0.05},
+	})
+	g.Legend = true
+	return g
+}
+
+// rightAxis returns the first overlay with its own range, only that one gets
+// labelled.
+func (g *Graph) rightAxis() *Overlay {
+	for _, o := range g.Overlays {
+		if o.Own {
+			return o
+		}
+	}
+	return nil
+}
+
+// drawLegend draws a single row of "name value" entries along the top of the
+// graph, dropping whatever doesn't fit.
+func (g *Graph) drawLegend(data *image.RGBA, x, y float64) {
+	type entry struct {
+		text string
+		clr  color.RGBA
+	}
+
+	entries := []entry{{g.Series.Name + " " + g.Label(), g.Color}}
+	for _, o := range g.Overlays {
+		entries = append(entries, entry{o.Series.Name + " " + o.Label(), o.Color})
+	}
+
+	tx := int(x)
+	for _, e := range entries {
+		width := len(e.text) * font.Width
+		if tx+width > int(x+g.Width) {
+			break
+		}
+		font.DrawString(data, tx, int(y), e.text, e.clr)
+		tx += width + (font.Width * 2)
+	}
+}
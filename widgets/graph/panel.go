@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+)
+
+// Panel stacks any number of graphs vertically on a single texture.
+type Panel struct {
+	Texture *texture.Texture
+	Graphs  []*Graph
+
+	Spacing    float64
+	Background color.RGBA
+}
+
+func NewPanel(program *shader.Program, x, y, width float64, graphs ...*Graph) *Panel {
+	p := &Panel{
+		Graphs:     graphs,
+		Spacing:    20,
+		Background: color.RGBA{0x33, 0x33, 0x33, 0xff},
+	}
+
+	height := 0.0
+	for i, graph := range graphs {
+		if i > 0 {
+			height += p.Spacing
+		}
+		height += graph.Height
+	}
+
+	p.Texture = &texture.Texture{X: x, Y: y, Width: width, Height: height}
+	p.Texture.Setup(program)
+	return p
+}
+
+func (p *Panel) Render() {
+	data := image.NewRGBA(image.Rect(0, 0, int(p.Texture.Width), int(p.Texture.Height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(p.Background)
+	draw2dkit.Rectangle(gc, 0, 0, p.Texture.Width, p.Texture.Height)
+	gc.Fill()
+
+	y := 0.0
+	for _, graph := range p.Graphs {
+		graph.Draw(gc, data, 0, y)
+		y += graph.Height + p.Spacing
+	}
+
+	p.Texture.Write(&data.Pix)
+}
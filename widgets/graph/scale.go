@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/maurodelazeri/harvey-gl/widgets"
+)
+
+type Scale int
+
+const (
+	// ScaleAllTime scales between the all-time minimum and maximum of the series.
+	ScaleAllTime Scale = iota
+	// ScaleFixed scales between Range.Min and Range.Max.
+	ScaleFixed
+	// ScaleWindow scales between the minimum and maximum of the visible samples.
+	ScaleWindow
+	// ScaleNice pads the visible extremes by Range.Padding and rounds them
+	// outwards to 1, 2 or 5 times a power of ten.
+	ScaleNice
+)
+
+// Range decides the vertical bounds a series is drawn between.
+type Range struct {
+	Scale   Scale
+	Min     float64
+	Max     float64
+	Padding float64
+}
+
+// Bounds returns the range for the given series when its last visible
+// samples are on screen. The returned range is never empty, so callers may
+// divide by max - min.
+func (r Range) Bounds(series *widgets.Series, visible int) (float64, float64) {
+	var min, max float64
+	switch r.Scale {
+	case ScaleFixed:
+		min, max = r.Min, r.Max
+	case ScaleAllTime:
+		min, max = series.Min, series.Max
+	default:
+		min, max = extremes(series.Last(visible))
+	}
+
+	min, max = protect(min, max)
+	if r.Scale == ScaleNice {
+		min, max = nice(min, max, r.Padding)
+	}
+	return min, max
+}
+
+// extremes ignores NaN samples, an empty or all-NaN window yields 0, 0.
+func extremes(values []float64) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		if math.IsNaN(value) {
+			continue
+		}
+		min = math.Min(min, value)
+		max = math.Max(max, value)
+	}
+	if min > max {
+		return 0, 0
+	}
+	return min, max
+}
+
+// protect turns degenerate ranges into usable ones: non finite bounds fall
+// back to 0..1, swapped bounds are swapped back, and a single value is
+// centered in a range 10% of its magnitude wide on either side, or -1..1 for
+// zero.
+func protect(min, max float64) (float64, float64) {
+	if math.IsNaN(min) || math.IsInf(min, 0) || math.IsNaN(max) || math.IsInf(max, 0) {
+		return 0, 1
+	}
+	if min > max {
+		min, max = max, min
+	}
+	if min == max {
+		pad := math.Abs(min) * 0.1
+		if pad == 0 {
+			pad = 1
+		}
+		return min - pad, max + pad
+	}
+	return min, max
+}
+
+func nice(min, max, padding float64) (float64, float64) {
+	pad := (max - min) * padding
+	min, max = min-pad, max+pad
+
+	step := niceNumber((max - min) / 4)
+	return math.Floor(min/step) * step, math.Ceil(max/step) * step
+}
+
+// niceNumber rounds value up to 1, 2 or 5 times a power of ten.
+func niceNumber(value float64) float64 {
+	exponent := math.Floor(math.Log10(value))
+	fraction := value / math.Pow(10, exponent)
+
+	switch {
+	case fraction <= 1:
+		fraction = 1
+	case fraction <= 2:
+		fraction = 2
+	case fraction <= 5:
+		fraction = 5
+	default:
+		fraction = 10
+	}
+	return fraction * math.Pow(10, exponent)
+}
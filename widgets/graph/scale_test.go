@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"math"
+	"testing"
+
+	"github.com/maurodelazeri/harvey-gl/widgets"
+)
+
+func series(values ...float64) *widgets.Series {
+	s := widgets.NewSeries("test", "", 100)
+	for _, value := range values {
+		s.Push(value)
+	}
+	return s
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestBounds(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name     string
+		r        Range
+		series   *widgets.Series
+		visible  int
+		min, max float64
+	}{
+		{"fixed", Range{Scale: ScaleFixed, Min: 0, Max: 100}, series(5), 10, 0, 100},
+		{"fixed swapped", Range{Scale: ScaleFixed, Min: 10, Max: 0}, series(5), 10, 0, 10},
+		{"fixed nan", Range{Scale: ScaleFixed, Min: nan, Max: 1}, series(5), 10, 0, 1},
+		{"all time", Range{Scale: ScaleAllTime}, series(2, 8, 4), 1, 2, 8},
+		{"window", Range{Scale: ScaleWindow}, series(5, 9, 1, 3), 2, 1, 3},
+		{"window skips nan", Range{Scale: ScaleWindow}, series(1, nan, 3), 3, 1, 3},
+		{"window empty", Range{Scale: ScaleWindow}, series(), 10, -1, 1},
+		{"window all nan", Range{Scale: ScaleWindow}, series(nan, nan), 10, -1, 1},
+		{"window negative count", Range{Scale: ScaleWindow}, series(1, 2), -5, -1, 1},
+		{"window single value", Range{Scale: ScaleWindow}, series(50, 50), 10, 45, 55},
+		{"nice", Range{Scale: ScaleNice}, series(3, 97), 10, 0, 100},
+		{"nice padded", Range{Scale: ScaleNice, Padding: 0.1}, series(10, 20), 10, 5, 25},
+		{"nice negative", Range{Scale: ScaleNice}, series(-7, 3), 10, -10, 5},
+	}
+	for _, test := range tests {
+		min, max := test.r.Bounds(test.series, test.visible)
+		if !near(min, test.min) || !near(max, test.max) {
+			t.Errorf("%s: got %v..%v, want %v..%v", test.name, min, max, test.min, test.max)
+		}
+	}
+}
+
+func TestNiceNumber(t *testing.T) {
+	tests := []struct {
+		value, want float64
+	}{
+		{0.3, 0.5},
+		{1, 1},
+		{1.5, 2},
+		{2, 2},
+		{3, 5},
+		{7, 10},
+		{120, 200},
+		{600, 1000},
+	}
+	for _, test := range tests {
+		if got := niceNumber(test.value); !near(got, test.want) {
+			t.Errorf("niceNumber(%v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
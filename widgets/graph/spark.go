@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+)
+
+// Sparkline draws the last width samples of series as a one pixel per sample
+// line inside the box at x, y, scaled to the visible samples. It is meant to
+// be embedded in other widgets, e.g. next to text in the status bar.
+func Sparkline(gc *draw2dimg.GraphicContext, series *widgets.Series, x, y, width, height float64, clr color.RGBA) {
+	visible := int(width)
+	values := series.Last(visible)
+	min, max := Range{Scale: ScaleWindow}.Bounds(series, visible)
+	area := plot{Left: x, Top: y, Right: x + width, Bottom: y + height - 1}
+
+	gc.SetStrokeColor(clr)
+	gc.SetLineWidth(1.0)
+
+	start := area.Right - float64(len(values))
+	moved := false
+	for i, value := range values {
+		if math.IsNaN(value) {
+			moved = false
+			continue
+		}
+		px, py := start+float64(i), area.scaled(value, min, max)
+		if !moved {
+			gc.MoveTo(px, py)
+			moved = true
+		} else {
+			gc.LineTo(px, py)
+		}
+	}
+	gc.Stroke()
+}
+
+// ProgressBar draws a horizontal bar filled to ratio, clamped to 0..1.
+func ProgressBar(gc *draw2dimg.GraphicContext, ratio, x, y, width, height float64, fg, bg color.RGBA) {
+	if ratio < 0 || math.IsNaN(ratio) {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+
+	gc.SetFillColor(bg)
+	draw2dkit.Rectangle(gc, x, y, x+width, y+height)
+	gc.Fill()
+
+	if ratio > 0 {
+		gc.SetFillColor(fg)
+		draw2dkit.Rectangle(gc, x, y, x+(ratio*width), y+height)
+		gc.Fill()
+	}
+}
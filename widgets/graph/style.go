@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+)
+
+type Style int
+
+const (
+	// StyleStep holds every sample for a full step, the classic look.
+	StyleStep Style = iota
+	// StyleLine connects samples with a smoothed curve.
+	StyleLine
+	// StyleArea fills below a straight line with a fading gradient.
+	StyleArea
+	// StyleBars draws one vertical bar per sample.
+	StyleBars
+	// StyleDots draws one dot per sample.
+	StyleDots
+)
+
+type point struct {
+	X float64
+	Y float64
+}
+
+func (g *Graph) drawStep(gc *draw2dimg.GraphicContext, points []point, step float64) {
+	for i, p := range points {
+		if i == 0 {
+			gc.MoveTo(p.X, p.Y)
+		} else {
+			gc.LineTo(p.X, p.Y)
+		}
+		gc.LineTo(p.X+step, p.Y)
+	}
+	gc.Stroke()
+}
+
+// drawLine draws a quadratic curve through the midpoints between samples,
+// using the samples themselves as control points.
+func (g *Graph) drawLine(gc *draw2dimg.GraphicContext, points []point) {
+	if len(points) == 0 {
+		return
+	}
+
+	gc.MoveTo(points[0].X, points[0].Y)
+	for i := 1; i < len(points)-1; i++ {
+		mx := (points[i].X + points[i+1].X) / 2
+		my := (points[i].Y + points[i+1].Y) / 2
+		gc.QuadCurveTo(points[i].X, points[i].Y, mx, my)
+	}
+	last := points[len(points)-1]
+	gc.LineTo(last.X, last.Y)
+	gc.Stroke()
+}
+
+func (g *Graph) drawArea(gc *draw2dimg.GraphicContext, data *image.RGBA, points []point, top, bottom float64, fill color.RGBA) {
+	if len(points) == 0 {
+		return
+	}
+
+	for i := 0; i < len(points)-1; i++ {
+		a, b := points[i], points[i+1]
+		for px := int(a.X); px < int(b.X); px++ {
+			ratio := (float64(px) - a.X) / (b.X - a.X)
+			fillGradient(data, px, a.Y+(b.Y-a.Y)*ratio, top, bottom, fill)
+		}
+	}
+
+	gc.MoveTo(points[0].X, points[0].Y)
+	for _, p := range points[1:] {
+		gc.LineTo(p.X, p.Y)
+	}
+	gc.Stroke()
+}
+
+func (g *Graph) drawBars(gc *draw2dimg.GraphicContext, points []point, step, bottom float64, fill color.RGBA) {
+	gc.SetFillColor(fill)
+	for _, p := range points {
+		draw2dkit.Rectangle(gc, p.X, p.Y, p.X+step-1, bottom)
+		gc.Fill()
+	}
+}
+
+func (g *Graph) drawDots(gc *draw2dimg.GraphicContext, points []point, clr color.RGBA) {
+	gc.SetFillColor(clr)
+	for _, p := range points {
+		draw2dkit.Circle(gc, p.X, p.Y, g.LineWidth+1)
+		gc.Fill()
+	}
+}
+
+// fillGradient fills the column px from y down to bottom, fading clr from its
+// own alpha at top to transparent at bottom.
+func fillGradient(data *image.RGBA, px int, y, top, bottom float64, clr color.RGBA) {
+	for py := int(y); py < int(bottom); py++ {
+		alpha := (float64(clr.A) / 0xff) * (bottom - float64(py)) / (bottom - top)
+		blend(data, px, py, clr, alpha)
+	}
+}
+
+func blend(data *image.RGBA, x, y int, clr color.RGBA, alpha float64) {
+	if !(image.Point{x, y}).In(data.Rect) {
+		return
+	}
+	dst := data.RGBAAt(x, y)
+	mix := func(s, d uint8) uint8 {
+		return uint8(float64(s)*alpha + float64(d)*(1-alpha))
+	}
+	data.SetRGBA(x, y, color.RGBA{mix(clr.R, dst.R), mix(clr.G, dst.G), mix(clr.B, dst.B), 0xff})
+}
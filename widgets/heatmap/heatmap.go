@@ -0,0 +1,133 @@
+package heatmap
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Heatmap draws one row per series and one column per sample, newest on the
+// right, colored by Ramp. It stays readable with far more series than an
+// overlaid graph, e.g. one row per CPU core.
+type Heatmap struct {
+	Texture *texture.Texture
+	Rows    []*widgets.Series
+
+	// Auto scales to the extremes of all visible samples, otherwise the ramp
+	// spans Min to Max.
+	Auto bool
+	Min  float64
+	Max  float64
+
+	Ramp       Ramp
+	CellWidth  int
+	Labels     bool
+	Background color.RGBA
+	LabelColor color.RGBA
+}
+
+func New(program *shader.Program, x, y, width, height float64, rows []*widgets.Series) *Heatmap {
+	h := &Heatmap{
+		Texture:    &texture.Texture{X: x, Y: y, Width: width, Height: height},
+		Rows:       rows,
+		Auto:       true,
+		Ramp:       RampHeat,
+		CellWidth:  4,
+		Labels:     true,
+		Background: color.RGBA{0x33, 0x33, 0x33, 0xff},
+		LabelColor: color.RGBA{0x99, 0x99, 0x99, 0xff},
+	}
+	h.Texture.Setup(program)
+	return h
+}
+
+// Fixed makes the ramp span min to max instead of the visible extremes.
+func (h *Heatmap) Fixed(min, max float64) *Heatmap {
+	h.Auto = false
+	h.Min = min
+	h.Max = max
+	return h
+}
+
+func (h *Heatmap) labelWidth() int {
+	if !h.Labels {
+		return 0
+	}
+	width := 0
+	for _, row := range h.Rows {
+		if len(row.Name) > width {
+			width = len(row.Name)
+		}
+	}
+	return (width + 1) * font.Width
+}
+
+func (h *Heatmap) bounds(columns int) (float64, float64) {
+	if !h.Auto {
+		return h.Min, h.Max
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, row := range h.Rows {
+		for _, value := range row.Last(columns) {
+			if math.IsNaN(value) {
+				continue
+			}
+			min = math.Min(min, value)
+			max = math.Max(max, value)
+		}
+	}
+	if min > max {
+		return 0, 1
+	}
+	return min, max
+}
+
+func (h *Heatmap) Render() {
+	data := image.NewRGBA(image.Rect(0, 0, int(h.Texture.Width), int(h.Texture.Height)))
+	draw.Draw(data, data.Rect, &image.Uniform{h.Background}, image.ZP, draw.Src)
+
+	if len(h.Rows) > 0 {
+		h.draw(data)
+	}
+
+	h.Texture.Write(&data.Pix)
+}
+
+func (h *Heatmap) draw(data *image.RGBA) {
+	left := h.labelWidth()
+	rowHeight := int(h.Texture.Height) / len(h.Rows)
+	columns := (int(h.Texture.Width) - left) / h.CellWidth
+
+	min, max := h.bounds(columns)
+	if max <= min {
+		max = min + 1
+	}
+
+	for r, row := range h.Rows {
+		top := r * rowHeight
+		values := row.Last(columns)
+		offset := columns - len(values)
+
+		for c, value := range values {
+			if math.IsNaN(value) {
+				continue
+			}
+			x := left + (offset+c)*h.CellWidth
+			cell := image.Rect(x, top, x+h.CellWidth, top+rowHeight)
+			clr := h.Ramp.At((value - min) / (max - min))
+			draw.Draw(data, cell, &image.Uniform{clr}, image.ZP, draw.Src)
+		}
+
+		if h.Labels && rowHeight >= font.Height {
+			font.DrawString(data, 0, top+((rowHeight-font.Height)/2), row.Name, h.LabelColor)
+		}
+	}
+}
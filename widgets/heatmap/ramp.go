@@ -0,0 +1,49 @@
+package heatmap
+
+import (
+	"image/color"
+)
+
+// Ramp maps a ratio between 0 and 1 to a color by interpolating between
+// evenly spaced stops.
+type Ramp []color.RGBA
+
+var (
+	RampHeat = Ramp{
+		{0x22, 0x22, 0x44, 0xff},
+		{0x66, 0x22, 0x88, 0xff},
+		{0xcc, 0x33, 0x33, 0xff},
+		{0xee, 0xaa, 0x22, 0xff},
+		{0xff, 0xff, 0xcc, 0xff},
+	}
+	RampGray = Ramp{
+		{0x33, 0x33, 0x33, 0xff},
+		{0xdd, 0xdd, 0xdd, 0xff},
+	}
+	RampTraffic = Ramp{
+		{0x33, 0x99, 0x33, 0xff},
+		{0xdd, 0xcc, 0x33, 0xff},
+		{0xcc, 0x33, 0x33, 0xff},
+	}
+)
+
+func (r Ramp) At(ratio float64) color.RGBA {
+	if len(r) == 0 {
+		return color.RGBA{}
+	}
+	if ratio <= 0 || len(r) == 1 {
+		return r[0]
+	}
+	if ratio >= 1 {
+		return r[len(r)-1]
+	}
+
+	pos := ratio * float64(len(r)-1)
+	i := int(pos)
+	frac := pos - float64(i)
+	a, b := r[i], r[i+1]
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*frac)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
+}
@@ -0,0 +1,122 @@
+package widgets
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MemoryInfo is the breakdown of /proc/meminfo in bytes, split up like
+// free(1) does: Used is what is neither free nor buffers or page cache, and
+// Cached includes the reclaimable slab.
+type MemoryInfo struct {
+	Total     uint64
+	Free      uint64
+	Available uint64
+	Used      uint64
+	Buffers   uint64
+	Cached    uint64
+	Shared    uint64
+	Dirty     uint64
+	Writeback uint64
+
+	SwapTotal uint64
+	SwapFree  uint64
+	SwapUsed  uint64
+}
+
+func readMeminfo() (map[string]uint64, error) {
+	buf, err := ioutil.ReadFile("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]uint64{}
+	for _, line := range strings.Split(string(buf), "\n") {
+		// MemTotal:       16303468 kB
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) == 3 && fields[2] == "kB" {
+			value *= 1024
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = value
+	}
+	return values, nil
+}
+
+func ReadMemory() (MemoryInfo, error) {
+	values, err := readMeminfo()
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+
+	m := MemoryInfo{
+		Total:     values["MemTotal"],
+		Free:      values["MemFree"],
+		Available: values["MemAvailable"],
+		Buffers:   values["Buffers"],
+		Cached:    values["Cached"] + values["SReclaimable"],
+		Shared:    values["Shmem"],
+		Dirty:     values["Dirty"],
+		Writeback: values["Writeback"],
+		SwapTotal: values["SwapTotal"],
+		SwapFree:  values["SwapFree"],
+	}
+	if used := m.Free + m.Buffers + m.Cached; used < m.Total {
+		m.Used = m.Total - used
+	}
+	if m.SwapFree < m.SwapTotal {
+		m.SwapUsed = m.SwapTotal - m.SwapFree
+	}
+	return m, nil
+}
+
+// readVmstat reads the counters of /proc/vmstat, "pswpin 1234" per line.
+func readVmstat() (map[string]uint64, error) {
+	buf, err := ioutil.ReadFile("/proc/vmstat")
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]uint64{}
+	for _, line := range strings.Split(string(buf), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+	return values, nil
+}
+
+func (s *Stats) UpdateMemory() {
+	m, err := ReadMemory()
+	if err != nil || m.Total == 0 {
+		return
+	}
+
+	s.MemoryInfo = m
+	s.Memory.Push(float64(m.Used) * 100 / float64(m.Total))
+	if m.SwapTotal > 0 {
+		s.Swap.Push(float64(m.SwapUsed) * 100 / float64(m.SwapTotal))
+	}
+
+	vmstat, err := readVmstat()
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	kib := func(pages float64) float64 { return pages * float64(os.Getpagesize()) / 1024 }
+	s.SwapIn.PushAt(kib(s.swapIn.Update(vmstat["pswpin"], now)), now)
+	s.SwapOut.PushAt(kib(s.swapOut.Update(vmstat["pswpout"], now)), now)
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+	"github.com/maurodelazeri/harvey-gl/widgets/graph"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Part is one stacked section of the memory bar.
+type Part struct {
+	Name  string
+	Color color.RGBA
+	Bytes func(m widgets.MemoryInfo) uint64
+}
+
+// Breakdown draws the memory as a bar stacked from used, shared, buffers and
+// page cache, so that real pressure stands out from cache that the kernel
+// can drop, with the swap usage and traffic below it.
+type Breakdown struct {
+	Texture *texture.Texture
+	Stats   *widgets.Stats
+	Parts   []Part
+
+	Color      color.RGBA
+	Background color.RGBA
+}
+
+func New(program *shader.Program, x, y, width, height float64, stats *widgets.Stats) *Breakdown {
+	b := &Breakdown{
+		Texture: &texture.Texture{X: x, Y: y, Width: width, Height: height},
+		Stats:   stats,
+		Parts: []Part{
+			// Shmem is accounted as page cache, it is shown on its own
+			// since it can't be dropped.
+			{"used", color.RGBA{0xcc, 0x66, 0x33, 0xff}, func(m widgets.MemoryInfo) uint64 { return m.Used }},
+			{"shared", color.RGBA{0xcc, 0xaa, 0x33, 0xff}, func(m widgets.MemoryInfo) uint64 { return m.Shared }},
+			{"buffers", color.RGBA{0x33, 0x66, 0x99, 0xff}, func(m widgets.MemoryInfo) uint64 { return m.Buffers }},
+			{"cached", color.RGBA{0x33, 0x99, 0x99, 0xff}, func(m widgets.MemoryInfo) uint64 {
+				if m.Cached < m.Shared {
+					return 0
+				}
+				return m.Cached - m.Shared
+			}},
+		},
+		Color:      color.RGBA{0x99, 0x99, 0x99, 0xff},
+		Background: color.RGBA{0x33, 0x33, 0x33, 0xff},
+	}
+	b.Texture.Setup(program)
+	return b
+}
+
+func gib(bytes uint64) float64 {
+	return float64(bytes) / (1 << 30)
+}
+
+func (b *Breakdown) Render() {
+	data := image.NewRGBA(image.Rect(0, 0, int(b.Texture.Width), int(b.Texture.Height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(b.Background)
+	draw2dkit.Rectangle(gc, 0, 0, b.Texture.Width, b.Texture.Height)
+	gc.Fill()
+
+	m := b.Stats.MemoryInfo
+	if m.Total == 0 {
+		b.Texture.Write(&data.Pix)
+		return
+	}
+
+	left := float64(font.Width)
+	width := b.Texture.Width - (left * 2)
+	y := 2
+
+	gc.SetFillColor(color.RGBA{0x44, 0x44, 0x44, 0xff})
+	draw2dkit.Rectangle(gc, left, float64(y), left+width, float64(y+font.Height))
+	gc.Fill()
+
+	x := left
+	lx := font.Width
+	for _, part := range b.Parts {
+		bytes := part.Bytes(m)
+		w := width * float64(bytes) / float64(m.Total)
+		gc.SetFillColor(part.Color)
+		draw2dkit.Rectangle(gc, x, float64(y), x+w, float64(y+font.Height))
+		gc.Fill()
+		x += w
+
+		label := fmt.Sprintf("%s %.1fG ", part.Name, gib(bytes))
+		lx, _ = font.DrawString(data, lx, y+font.Height+2, label, part.Color)
+	}
+	y += (font.Height * 2) + 2
+
+	line := fmt.Sprintf("%.1f/%.1fG available  dirty %.0fM writeback %.0fM", gib(m.Available), gib(m.Total),
+		float64(m.Dirty)/(1<<20), float64(m.Writeback)/(1<<20))
+	font.DrawString(data, font.Width, y, line, b.Color)
+	y += font.Height
+
+	if m.SwapTotal > 0 {
+		line = fmt.Sprintf("swap %.1f/%.1fG  in %.0f out %.0f KiB/s", gib(m.SwapUsed), gib(m.SwapTotal),
+			b.Stats.SwapIn.Value, b.Stats.SwapOut.Value)
+	} else {
+		line = "no swap"
+	}
+	font.DrawString(data, font.Width, y, line, b.Color)
+	y += font.Height
+
+	bg := color.RGBA{0x44, 0x44, 0x44, 0xff}
+	graph.ProgressBar(gc, b.Stats.Swap.Value/100, left, float64(y+2), width, 4, b.Color, bg)
+
+	b.Texture.Write(&data.Pix)
+}
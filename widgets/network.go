@@ -0,0 +1,137 @@
+package widgets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Interface is the state of a network interface as found in /sys/class/net,
+// with the link quality from /proc/net/wireless for wireless ones.
+type Interface struct {
+	Name      string
+	OperState string
+	Carrier   bool
+	Speed     int // Mb/s, 0 when unknown
+	MTU       int
+
+	IPv4 []string
+	IPv6 []string
+
+	// Quality is the link quality in percent, Signal and Noise are in dBm.
+	Wireless bool
+	Quality  float64
+	Signal   float64
+	Noise    float64
+}
+
+// Up tells whether the interface is up with a carrier.
+func (i Interface) Up() bool {
+	return i.OperState == "up" || (i.OperState == "unknown" && i.Carrier)
+}
+
+const netPath = "/sys/class/net"
+
+func ReadInterfaces() ([]Interface, error) {
+	dirs, err := ioutil.ReadDir(netPath)
+	if err != nil {
+		return nil, err
+	}
+
+	wireless := readWireless()
+
+	var interfaces []Interface
+	for _, dir := range dirs {
+		iface := Interface{Name: dir.Name()}
+		iface.OperState = readNetFile(iface.Name, "operstate")
+		iface.Carrier = readNetFile(iface.Name, "carrier") == "1"
+		iface.MTU, _ = strconv.Atoi(readNetFile(iface.Name, "mtu"))
+
+		// Reading the speed fails while the link is down, and virtual
+		// interfaces report -1.
+		if speed, err := strconv.Atoi(readNetFile(iface.Name, "speed")); err == nil && speed > 0 {
+			iface.Speed = speed
+		}
+
+		if w, ok := wireless[iface.Name]; ok {
+			iface.Wireless = true
+			iface.Quality, iface.Signal, iface.Noise = w.Quality, w.Signal, w.Noise
+		}
+
+		iface.IPv4, iface.IPv6 = addresses(iface.Name)
+		interfaces = append(interfaces, iface)
+	}
+	return interfaces, nil
+}
+
+func readNetFile(name, file string) string {
+	buf, err := ioutil.ReadFile(fmt.Sprintf("%s/%s/%s", netPath, name, file))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+func addresses(name string) (ipv4, ipv6 []string) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, nil
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, nil
+	}
+
+	for _, addr := range addrs {
+		ip, _, err := net.ParseCIDR(addr.String())
+		if err != nil {
+			continue
+		}
+		if ip.To4() != nil {
+			ipv4 = append(ipv4, addr.String())
+		} else {
+			ipv6 = append(ipv6, addr.String())
+		}
+	}
+	return ipv4, ipv6
+}
+
+// readWireless parses /proc/net/wireless, which after two header lines has
+// one line per wireless interface:
+//
+//	wlp3s0: 0000   54.  -56.  -256        0      0      0      0     10        0
+//
+// The link quality is out of 70 for most drivers.
+func readWireless() map[string]Interface {
+	wireless := map[string]Interface{}
+
+	buf, err := ioutil.ReadFile("/proc/net/wireless")
+	if err != nil {
+		return wireless
+	}
+
+	lines := strings.Split(string(buf), "\n")
+	if len(lines) < 3 {
+		return wireless
+	}
+	for _, line := range lines[2:] {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+
+		number := func(s string) float64 {
+			value, _ := strconv.ParseFloat(strings.TrimSuffix(s, "."), 64)
+			return value
+		}
+		wireless[strings.TrimSuffix(fields[0], ":")] = Interface{
+			Quality: math.Min(number(fields[2])*100/70, 100),
+			Signal:  number(fields[3]),
+			Noise:   number(fields[4]),
+		}
+	}
+	return wireless
+}
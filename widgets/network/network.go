@@ -0,0 +1,113 @@
+package network
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/config"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Details lists the selected network interfaces with their link state and
+// addresses, wireless ones with a signal strength glyph.
+type Details struct {
+	Texture *texture.Texture
+	Stats   *widgets.Stats
+	Config  config.Network
+
+	Color      color.RGBA
+	DownColor  color.RGBA
+	Background color.RGBA
+}
+
+func New(program *shader.Program, x, y, width, height float64, stats *widgets.Stats, cfg config.Network) *Details {
+	d := &Details{
+		Texture:    &texture.Texture{X: x, Y: y, Width: width, Height: height},
+		Stats:      stats,
+		Config:     cfg,
+		Color:      color.RGBA{0x99, 0x99, 0x99, 0xff},
+		DownColor:  color.RGBA{0x66, 0x66, 0x66, 0xff},
+		Background: color.RGBA{0x33, 0x33, 0x33, 0xff},
+	}
+	d.Texture.Setup(program)
+	return d
+}
+
+func lines(name string, iface widgets.Interface) []string {
+	if name != iface.Name {
+		name = fmt.Sprintf("%s (%s)", name, iface.Name)
+	}
+
+	link := iface.OperState
+	if iface.Speed > 0 {
+		link += fmt.Sprintf(" %dMb/s", iface.Speed)
+	}
+	if iface.Wireless {
+		link += fmt.Sprintf(" %.0fdBm %.0f%%", iface.Signal, iface.Quality)
+	}
+
+	addresses := strings.Join(append(iface.IPv4, iface.IPv6...), " ")
+	if addresses == "" {
+		addresses = "no address"
+	}
+
+	return []string{
+		fmt.Sprintf("%s %s mtu %d", name, link, iface.MTU),
+		addresses,
+	}
+}
+
+// drawSignal draws four bars of rising height, as many filled as the quality
+// reaches quarters.
+func drawSignal(gc *draw2dimg.GraphicContext, quality, x, y, height float64, fg, bg color.RGBA) {
+	bar := height / 4
+	for i := 0; i < 4; i++ {
+		gc.SetFillColor(bg)
+		if quality >= float64(i+1)*25-12.5 {
+			gc.SetFillColor(fg)
+		}
+		top := y + height - (bar * float64(i+1))
+		draw2dkit.Rectangle(gc, x+float64(i)*3, top, x+float64(i)*3+2, y+height)
+		gc.Fill()
+	}
+}
+
+func (d *Details) Render() {
+	data := image.NewRGBA(image.Rect(0, 0, int(d.Texture.Width), int(d.Texture.Height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(d.Background)
+	draw2dkit.Rectangle(gc, 0, 0, d.Texture.Width, d.Texture.Height)
+	gc.Fill()
+
+	y := 2
+	glyph := font.Width * 2
+	bg := color.RGBA{0x44, 0x44, 0x44, 0xff}
+	for _, iface := range d.Stats.Interfaces {
+		if !d.Config.Selects(iface.Name) {
+			continue
+		}
+
+		clr := d.Color
+		if !iface.Up() {
+			clr = d.DownColor
+		}
+		if iface.Wireless {
+			drawSignal(gc, iface.Quality, float64(font.Width), float64(y+1), float64(font.Height-3), clr, bg)
+		}
+		for _, line := range lines(d.Config.Name(iface.Name), iface) {
+			font.DrawString(data, font.Width+glyph, y, line, clr)
+			y += font.Height
+		}
+	}
+
+	d.Texture.Write(&data.Pix)
+}
@@ -0,0 +1,107 @@
+package widgets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// The power_supply types, as reported in POWER_SUPPLY_TYPE.
+const (
+	SupplyMains   = "Mains"
+	SupplyBattery = "Battery"
+	SupplyUSB     = "USB"
+	SupplyUPS     = "UPS"
+)
+
+// PowerSupply is an entry of /sys/class/power_supply, an AC adapter, a USB-C
+// supply, a UPS or a battery. The name doesn't tell which, adapters show up as
+// AC, AC0, ADP1 or ACAD depending on the firmware, so they are told apart by
+// their type.
+type PowerSupply struct {
+	Name   string
+	Type   string
+	Online bool
+
+	vars map[string]string
+}
+
+// External is true for supplies that power the machine from the outside.
+func (p PowerSupply) External() bool {
+	return p.Type == SupplyMains || p.Type == SupplyUSB || p.Type == SupplyUPS
+}
+
+// ReadPowerSupplies reads every power supply. Entries that can't be read, e.g.
+// while a dock is being detached, are left out.
+func ReadPowerSupplies() ([]PowerSupply, error) {
+	dirs, err := ioutil.ReadDir(batteryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var supplies []PowerSupply
+	for _, dir := range dirs {
+		vars, err := readUevent(dir.Name())
+		if err != nil {
+			continue
+		}
+
+		// Older kernels and some drivers leave the type and online state out
+		// of uevent, the attribute files always have them.
+		supplyType, ok := vars["POWER_SUPPLY_TYPE"]
+		if !ok {
+			supplyType = readSupplyFile(dir.Name(), "type")
+		}
+		online, ok := vars["POWER_SUPPLY_ONLINE"]
+		if !ok {
+			online = readSupplyFile(dir.Name(), "online")
+		}
+
+		supplies = append(supplies, PowerSupply{
+			Name:   dir.Name(),
+			Type:   supplyType,
+			Online: online == "1",
+			vars:   vars,
+		})
+	}
+	return supplies, nil
+}
+
+func readSupplyFile(name, file string) string {
+	buf, err := ioutil.ReadFile(fmt.Sprintf("%s/%s/%s", batteryPath, name, file))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// Batteries parses the batteries among supplies.
+func Batteries(supplies []PowerSupply) []BatteryStatus {
+	var batteries []BatteryStatus
+	for _, supply := range supplies {
+		if supply.Type == SupplyBattery {
+			batteries = append(batteries, *parseBattery(supply.Name, supply.vars))
+		}
+	}
+	return batteries
+}
+
+// OnlineAC tells whether the machine runs on external power. Without any
+// external supply to ask, e.g. on some tablets, it goes by the battery pack
+// not discharging.
+func OnlineAC(supplies []PowerSupply, pack *BatteryStatus) bool {
+	external := false
+	for _, supply := range supplies {
+		if !supply.External() {
+			continue
+		}
+		if supply.Online {
+			return true
+		}
+		external = true
+	}
+	if external {
+		return false
+	}
+	return pack == nil || pack.Status != "Discharging"
+}
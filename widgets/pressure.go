@@ -0,0 +1,122 @@
+package widgets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PressureLine is a line of a /proc/pressure file: the share of time in
+// percent that some or all tasks were stalled, averaged over 10, 60 and 300
+// seconds, and the total stall time in µs.
+type PressureLine struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
+// Pressure is the Pressure Stall Information of a resource, "cpu", "memory"
+// or "io". SomeStall and FullStall are the shares of time stalled between
+// samples, from the growth of the totals. The cpu has no full line before
+// Linux 5.13, and kernels without PSI have no files at all; Available is
+// false then.
+type Pressure struct {
+	Resource  string
+	Available bool
+	Some      PressureLine
+	Full      PressureLine
+
+	SomeStall *Series
+	FullStall *Series
+
+	some *Counter
+	full *Counter
+}
+
+var PressureResources []string = []string{"cpu", "memory", "io"}
+
+func newPressure(resource string) *Pressure {
+	return &Pressure{
+		Resource:  resource,
+		SomeStall: NewSeries(resource+" some", "%", 60),
+		FullStall: NewSeries(resource+" full", "%", 60),
+		some:      NewCounter(time.Second * 30),
+		full:      NewCounter(time.Second * 30),
+	}
+}
+
+// parsePressureLine parses "some avg10=0.00 avg60=0.00 avg300=0.00 total=0".
+func parsePressureLine(fields []string) PressureLine {
+	line := PressureLine{}
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "avg10":
+			line.Avg10, _ = strconv.ParseFloat(kv[1], 64)
+		case "avg60":
+			line.Avg60, _ = strconv.ParseFloat(kv[1], 64)
+		case "avg300":
+			line.Avg300, _ = strconv.ParseFloat(kv[1], 64)
+		case "total":
+			line.Total, _ = strconv.ParseUint(kv[1], 10, 64)
+		}
+	}
+	return line
+}
+
+func (p *Pressure) update(now time.Time) {
+	buf, err := ioutil.ReadFile(fmt.Sprintf("/proc/pressure/%s", p.Resource))
+	if err != nil {
+		p.Available = false
+		return
+	}
+	p.Available = true
+
+	for _, line := range strings.Split(string(buf), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "some":
+			p.Some = parsePressureLine(fields[1:])
+		case "full":
+			p.Full = parsePressureLine(fields[1:])
+		}
+	}
+
+	// µs stalled per second, as percent.
+	p.SomeStall.PushAt(p.some.Update(p.Some.Total, now)/10000, now)
+	p.FullStall.PushAt(p.full.Update(p.Full.Total, now)/10000, now)
+}
+
+func (s *Stats) UpdatePressure() {
+	if s.Pressure == nil {
+		for _, resource := range PressureResources {
+			s.Pressure = append(s.Pressure, newPressure(resource))
+		}
+	}
+
+	now := time.Now()
+	for _, p := range s.Pressure {
+		p.update(now)
+	}
+}
+
+// Stalling returns the resource with the highest some avg10, or nil when
+// nothing stalls for at least threshold percent.
+func (s *Stats) Stalling(threshold float64) *Pressure {
+	var worst *Pressure
+	for _, p := range s.Pressure {
+		if p.Available && p.Some.Avg10 >= threshold && (worst == nil || p.Some.Avg10 > worst.Some.Avg10) {
+			worst = p
+		}
+	}
+	return worst
+}
@@ -0,0 +1,88 @@
+package pressure
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+	"github.com/maurodelazeri/harvey-gl/widgets/graph"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+// Stalls shows a row per resource with its some and full avg10 pressure and
+// a sparkline of the stall time, and names the resource the system is
+// stalling on, if any.
+type Stalls struct {
+	Texture *texture.Texture
+	Stats   *widgets.Stats
+
+	// Threshold is the some avg10 in percent from which a resource counts as
+	// stalling.
+	Threshold float64
+
+	Color      color.RGBA
+	StallColor color.RGBA
+	Background color.RGBA
+}
+
+func New(program *shader.Program, x, y, width, height float64, stats *widgets.Stats) *Stalls {
+	s := &Stalls{
+		Texture:    &texture.Texture{X: x, Y: y, Width: width, Height: height},
+		Stats:      stats,
+		Threshold:  10,
+		Color:      color.RGBA{0x99, 0x99, 0x99, 0xff},
+		StallColor: color.RGBA{0xcc, 0x33, 0x33, 0xff},
+		Background: color.RGBA{0x33, 0x33, 0x33, 0xff},
+	}
+	s.Texture.Setup(program)
+	return s
+}
+
+func (s *Stalls) Render() {
+	data := image.NewRGBA(image.Rect(0, 0, int(s.Texture.Width), int(s.Texture.Height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(s.Background)
+	draw2dkit.Rectangle(gc, 0, 0, s.Texture.Width, s.Texture.Height)
+	gc.Fill()
+
+	y := 2
+	stalling := s.Stats.Stalling(s.Threshold)
+	switch {
+	case len(s.Stats.Pressure) == 0 || !s.Stats.Pressure[0].Available:
+		font.DrawString(data, font.Width, y, "no pressure information", s.Color)
+	case stalling != nil:
+		font.DrawString(data, font.Width, y, "stalling on "+stalling.Resource, s.StallColor)
+	default:
+		font.DrawString(data, font.Width, y, "no stall", s.Color)
+	}
+	y += font.Height
+	font.DrawString(data, font.Width, y, fmt.Sprintf("%-6s %5s %5s %5s", "", "some", "full", "5m"), s.Color)
+	y += font.Height
+
+	sparkWidth := 60.0
+	for _, p := range s.Stats.Pressure {
+		if !p.Available {
+			continue
+		}
+
+		clr := s.Color
+		if p == stalling {
+			clr = s.StallColor
+		}
+		line := fmt.Sprintf("%-6s %5.1f %5.1f %5.1f", p.Resource, p.Some.Avg10, p.Full.Avg10, p.Some.Avg300)
+		x, _ := font.DrawString(data, font.Width, y, line, clr)
+
+		x += font.Width
+		graph.Sparkline(gc, p.SomeStall, float64(x), float64(y+2), sparkWidth, float64(font.Height-4), clr)
+		y += font.Height
+	}
+
+	s.Texture.Write(&data.Pix)
+}
@@ -0,0 +1,142 @@
+package widgets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Process is a process as read from /proc/[pid]. CPU is in percent of one
+// core, IO in bytes/s read and written to storage; IO is NaN for processes
+// of other users, whose io file can't be read.
+type Process struct {
+	PID  int
+	Name string
+	User string
+
+	CPU float64
+	RSS uint64
+	IO  float64
+
+	// History is the recent CPU usage.
+	History *Series
+
+	cpu *Counter
+	io  *Counter
+}
+
+// clockTicks is USER_HZ, which is 100 on every architecture Linux runs on
+// today.
+const clockTicks = 100
+
+var usernames map[uint32]string = map[uint32]string{}
+
+func username(uid uint32) string {
+	if name, ok := usernames[uid]; ok {
+		return name
+	}
+	name := strconv.Itoa(int(uid))
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	usernames[uid] = name
+	return name
+}
+
+func readStat(pid int) (name string, ticks, rss uint64, err error) {
+	buf, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return parseStat(string(buf))
+}
+
+// parseStat parses /proc/[pid]/stat. The name is in parentheses and may itself
+// contain spaces and parentheses, so the fields are split after the last one.
+func parseStat(stat string) (name string, ticks, rss uint64, err error) {
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return "", 0, 0, fmt.Errorf("malformed stat %q", stat)
+	}
+	name = stat[open+1 : end]
+
+	// Starting with the state, the third field.
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 22 {
+		return "", 0, 0, fmt.Errorf("malformed stat %q", stat)
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	pages, _ := strconv.ParseUint(fields[21], 10, 64)
+	return name, utime + stime, pages * uint64(os.Getpagesize()), nil
+}
+
+// readIO sums read_bytes and write_bytes of /proc/[pid]/io.
+func readIO(pid int) (uint64, bool) {
+	buf, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/io", pid))
+	if err != nil {
+		return 0, false
+	}
+	total := uint64(0)
+	for _, line := range strings.Split(string(buf), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && (fields[0] == "read_bytes:" || fields[0] == "write_bytes:") {
+			value, _ := strconv.ParseUint(fields[1], 10, 64)
+			total += value
+		}
+	}
+	return total, true
+}
+
+func (s *Stats) UpdateProcesses() {
+	dirs, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return
+	}
+
+	processes := map[int]*Process{}
+	now := time.Now()
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(dir.Name())
+		if err != nil || !dir.IsDir() {
+			continue
+		}
+
+		name, ticks, rss, err := readStat(pid)
+		if err != nil {
+			// The process exited meanwhile.
+			continue
+		}
+
+		p, ok := s.Processes[pid]
+		if !ok || p.Name != name {
+			p = &Process{
+				PID:     pid,
+				Name:    name,
+				History: NewSeries(name, "%", 30),
+				cpu:     NewCounter(time.Second * 30),
+				io:      NewCounter(time.Second * 30),
+			}
+			if st, ok := dir.Sys().(*syscall.Stat_t); ok {
+				p.User = username(st.Uid)
+			}
+		}
+		processes[pid] = p
+
+		p.RSS = rss
+		p.CPU = p.cpu.Update(ticks, now) * 100 / clockTicks
+		p.History.PushAt(p.CPU, now)
+
+		p.IO = math.NaN()
+		if io, ok := readIO(pid); ok {
+			p.IO = p.io.Update(io, now)
+		}
+	}
+	s.Processes = processes
+}
@@ -0,0 +1,144 @@
+package process
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/shader"
+	"github.com/maurodelazeri/harvey-gl/texture"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+	"github.com/maurodelazeri/harvey-gl/widgets/graph"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+type SortKey int
+
+const (
+	SortCPU SortKey = iota
+	SortRSS
+	SortIO
+)
+
+func (k SortKey) value(p *widgets.Process) float64 {
+	switch k {
+	case SortRSS:
+		return float64(p.RSS)
+	case SortIO:
+		if math.IsNaN(p.IO) {
+			return -1
+		}
+		return p.IO
+	default:
+		if math.IsNaN(p.CPU) {
+			return -1
+		}
+		return p.CPU
+	}
+}
+
+// Table lists the top processes by the sort key, one row per process with a
+// sparkline of its CPU usage.
+type Table struct {
+	Texture *texture.Texture
+	Stats   *widgets.Stats
+	Sort    SortKey
+	Rows    int
+
+	Color       color.RGBA
+	HeaderColor color.RGBA
+	Background  color.RGBA
+}
+
+func New(program *shader.Program, x, y, width, height float64, stats *widgets.Stats) *Table {
+	t := &Table{
+		Texture:     &texture.Texture{X: x, Y: y, Width: width, Height: height},
+		Stats:       stats,
+		Rows:        (int(height)-4)/font.Height - 1,
+		Color:       color.RGBA{0x99, 0x99, 0x99, 0xff},
+		HeaderColor: color.RGBA{0xcc, 0xcc, 0xcc, 0xff},
+		Background:  color.RGBA{0x33, 0x33, 0x33, 0xff},
+	}
+	t.Texture.Setup(program)
+	return t
+}
+
+// SortBy switches the sort key.
+func (t *Table) SortBy(key SortKey) {
+	t.Sort = key
+}
+
+// Next switches to the next sort key.
+func (t *Table) Next() {
+	t.Sort = (t.Sort + 1) % (SortIO + 1)
+}
+
+// Top returns the first Rows processes by the sort key.
+func (t *Table) Top() []*widgets.Process {
+	processes := make([]*widgets.Process, 0, len(t.Stats.Processes))
+	for _, p := range t.Stats.Processes {
+		processes = append(processes, p)
+	}
+	sort.Slice(processes, func(i, j int) bool {
+		a, b := t.Sort.value(processes[i]), t.Sort.value(processes[j])
+		if a != b {
+			return a > b
+		}
+		return processes[i].PID < processes[j].PID
+	})
+	if len(processes) > t.Rows {
+		processes = processes[:t.Rows]
+	}
+	return processes
+}
+
+func rate(bytes float64) string {
+	switch {
+	case math.IsNaN(bytes):
+		return "-"
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1fM", bytes/(1<<20))
+	default:
+		return fmt.Sprintf("%.0fK", bytes/(1<<10))
+	}
+}
+
+func (t *Table) Render() {
+	data := image.NewRGBA(image.Rect(0, 0, int(t.Texture.Width), int(t.Texture.Height)))
+	gc := draw2dimg.NewGraphicContext(data)
+
+	gc.SetFillColor(t.Background)
+	draw2dkit.Rectangle(gc, 0, 0, t.Texture.Width, t.Texture.Height)
+	gc.Fill()
+
+	// The sort column is marked with a *.
+	columns := []string{" CPU%", " RSS", " IO/s"}
+	columns[t.Sort] = "*" + columns[t.Sort][1:]
+	header := fmt.Sprintf("%6s %-8s %-15s %5s %6s %6s", "PID", "USER", "NAME", columns[0], columns[1], columns[2])
+
+	y := 2
+	x, _ := font.DrawString(data, font.Width, y, header, t.HeaderColor)
+	sparkX := float64(x + font.Width)
+	sparkWidth := t.Texture.Width - sparkX - float64(font.Width)
+	y += font.Height
+
+	for _, p := range t.Top() {
+		cpu := "-"
+		if !math.IsNaN(p.CPU) {
+			cpu = fmt.Sprintf("%.1f", p.CPU)
+		}
+		line := fmt.Sprintf("%6d %-8.8s %-15.15s %5s %6s %6s", p.PID, p.User, p.Name, cpu, rate(float64(p.RSS)), rate(p.IO))
+		font.DrawString(data, font.Width, y, line, t.Color)
+		if sparkWidth > 0 {
+			graph.Sparkline(gc, p.History, sparkX, float64(y+2), sparkWidth, float64(font.Height-4), t.Color)
+		}
+		y += font.Height
+	}
+
+	t.Texture.Write(&data.Pix)
+}
@@ -0,0 +1,40 @@
+package widgets
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseStat(t *testing.T) {
+	// The fields after the name up to rss, with utime 7, stime 3 and 282
+	// resident pages.
+	const rest = " S 1 2 3 0 -1 4194304 82 0 0 0 7 3 0 0 20 0 1 0 249444 2703360 282 18446744073709551615 0 0"
+	page := uint64(os.Getpagesize())
+
+	tests := []struct {
+		name  string
+		stat  string
+		want  string
+		ticks uint64
+		rss   uint64
+		err   bool
+	}{
+		{"plain", "42 (cat)" + rest, "cat", 10, 282 * page, false},
+		{"spaces", "42 (Web Content)" + rest, "Web Content", 10, 282 * page, false},
+		{"parentheses", "42 (a) b (c))" + rest, "a) b (c)", 10, 282 * page, false},
+		{"empty name", "42 ()" + rest, "", 10, 282 * page, false},
+		{"no parentheses", "42 cat" + rest, "", 0, 0, true},
+		{"truncated", "42 (cat) S 1 2 3", "", 0, 0, true},
+		{"empty", "", "", 0, 0, true},
+	}
+	for _, test := range tests {
+		name, ticks, rss, err := parseStat(test.stat)
+		if (err != nil) != test.err {
+			t.Errorf("%s: got error %v", test.name, err)
+			continue
+		}
+		if name != test.want || ticks != test.ticks || rss != test.rss {
+			t.Errorf("%s: got %q %d %d, want %q %d %d", test.name, name, ticks, rss, test.want, test.ticks, test.rss)
+		}
+	}
+}
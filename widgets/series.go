@@ -0,0 +1,94 @@
+package widgets
+
+import (
+	"math"
+	"time"
+)
+
+// Series is a bounded history of samples for a single metric, together with
+// the current value and the all-time extremes. A NaN sample is unknown, e.g.
+// a rate across a counter reset; it is kept in the history so graphs show a
+// gap, but leaves the current value and the extremes alone.
+type Series struct {
+	Name     string
+	Unit     string
+	Values   []float64
+	Times    []time.Time
+	MaxCount int
+
+	Value float64
+	Min   float64
+	Max   float64
+
+	seen bool
+}
+
+func NewSeries(name, unit string, maxCount int) *Series {
+	return &Series{
+		Name:     name,
+		Unit:     unit,
+		MaxCount: maxCount,
+	}
+}
+
+func (s *Series) Push(value float64) {
+	s.PushAt(value, time.Now())
+}
+
+func (s *Series) PushAt(value float64, t time.Time) {
+	if math.IsNaN(value) {
+		s.append(value, t)
+		return
+	}
+	s.Value = value
+
+	if !s.seen || value > s.Max {
+		s.Max = value
+	}
+
+	if !s.seen || value < s.Min {
+		s.Min = value
+	}
+	s.seen = true
+	s.append(value, t)
+}
+
+func (s *Series) append(value float64, t time.Time) {
+	if len(s.Values) >= s.MaxCount {
+		s.Values = append(s.Values[1:], value)
+		s.Times = append(s.Times[1:], t)
+	} else {
+		s.Values = append(s.Values, value)
+		s.Times = append(s.Times, t)
+	}
+}
+
+// Last returns up to n of the most recent values, none for a negative n.
+func (s *Series) Last(n int) []float64 {
+	return s.Values[lastStart(len(s.Values), n):]
+}
+
+// Latest returns the most recent sample, NaN while there is none or it is
+// unknown, where Value keeps the last known one.
+func (s *Series) Latest() float64 {
+	if len(s.Values) == 0 {
+		return math.NaN()
+	}
+	return s.Values[len(s.Values)-1]
+}
+
+// LastTimes returns the timestamps matching Last(n).
+func (s *Series) LastTimes(n int) []time.Time {
+	return s.Times[lastStart(len(s.Times), n):]
+}
+
+// lastStart is the index of the last n of length elements, with n clamped to
+// 0 and length.
+func lastStart(length, n int) int {
+	if n < 0 {
+		n = 0
+	} else if n > length {
+		n = length
+	}
+	return length - n
+}
@@ -4,21 +4,26 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"log"
 	"math"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/config"
 	"github.com/maurodelazeri/harvey-gl/shader"
 	"github.com/maurodelazeri/harvey-gl/texture"
 	"github.com/maurodelazeri/harvey-gl/widgets"
-	"github.com/llgcode/draw2d/draw2dimg"
-	"github.com/llgcode/draw2d/draw2dkit"
 
 	font "github.com/maurodelazeri/harvey-gl/font/terminus"
 
 	psutil_net "github.com/shirou/gopsutil/net"
 )
 
+// Net is an interface, or a group of interfaces, with its byte counters and
+// rates. The rates are NaN while unknown.
 type Net struct {
 	Name          string
 	Time          time.Time
@@ -26,30 +31,111 @@ type Net struct {
 	LastBytesSent uint64
 	RateRecv      float64
 	RateSent      float64
+
+	recv *widgets.Counter
+	sent *widgets.Counter
 }
 
+// NetworkMaxGap is the longest time between two samples of an interface that
+// still gives a rate, longer gaps are taken as a suspend.
+var NetworkMaxGap time.Duration = time.Second * 30
+
+// Status is locked while it is updated, whoever renders it locks it as well,
+// after its Stats.
 type Status struct {
-	Texture    *texture.Texture
-	Redraw     chan bool
-	Time       string
-	Network    string
-	NetworkMap map[string]*Net
-	Battery    string
-	Stats      *widgets.Stats
+	sync.Mutex
+
+	Texture  *texture.Texture
+	Redraw   chan bool
+	Time     string
+	Stats    *widgets.Stats
+	Segments []*Segment
+
+	// Networks are the interfaces shown, in order and by the name they are
+	// shown as, and NetworkMap the same by name. Network is their summary.
+	Network       string
+	Networks      []*Net
+	NetworkMap    map[string]*Net
+	NetworkConfig config.Network
+
+	// Rates holds the received plus sent KiB/s of Networks, in the same
+	// order. rates keeps them by name while the network is shown.
+	Rates []*widgets.Series
+	rates map[string]*widgets.Series
+
+	// Accounting keeps the traffic totals of the selected interfaces, Quotas
+	// are checked against them.
+	Accounting *widgets.Accounting
+	Quotas     []*Quota
+
+	// today and month are the traffic totals of Networks by name. They are
+	// built anew on every update, the accounting maps are only touched by
+	// Run.
+	today map[string]widgets.Traffic
+	month map[string]widgets.Traffic
+
+	// interfaces holds every selected interface by its own name.
+	interfaces map[string]*Net
+
+	// quit stops Run, which closes stopped when it returns.
+	quit    chan bool
+	stopped chan bool
 }
 
 var FontPadding int = 3
 
-func New(windowWidth, windowHeight int, program *shader.Program, stats *widgets.Stats) *Status {
+func New(windowWidth, windowHeight int, program *shader.Program, stats *widgets.Stats, cfg *config.Config) (*Status, error) {
 	height := float64(font.Height + (2 * FontPadding))
 	status := &Status{
-		Texture:    &texture.Texture{X: 0, Y: float64(windowHeight), Width: float64(windowWidth), Height: height},
-		Redraw:     make(chan bool),
-		NetworkMap: map[string]*Net{},
-		Stats:      stats,
+		Texture:       &texture.Texture{X: 0, Y: float64(windowHeight), Width: float64(windowWidth), Height: height},
+		Redraw:        make(chan bool),
+		NetworkMap:    map[string]*Net{},
+		NetworkConfig: cfg.Network,
+		Stats:         stats,
+		interfaces:    map[string]*Net{},
+		rates:         map[string]*widgets.Series{},
+		quit:          make(chan bool),
+		stopped:       make(chan bool),
+	}
+
+	accounting, err := widgets.LoadAccounting(cfg.Accounting.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load traffic totals: %v", err)
+	}
+	status.Accounting = accounting
+	for _, c := range cfg.Accounting.Quotas {
+		q, err := newQuota(c)
+		if err != nil {
+			return nil, err
+		}
+		status.Quotas = append(status.Quotas, q)
+	}
+
+	for _, c := range cfg.Status.Segments {
+		seg, err := NewSegment(c)
+		if err != nil {
+			return nil, err
+		}
+		status.Add(seg)
 	}
+
 	status.Texture.Setup(program)
-	return status
+	return status, nil
+}
+
+// Add appends a segment, it is drawn last in its alignment group.
+func (s *Status) Add(seg *Segment) {
+	s.Segments = append(s.Segments, seg)
+}
+
+// Segment returns the segment with the given name, or nil.
+func (s *Status) Segment(name string) *Segment {
+	for _, seg := range s.Segments {
+		if seg.Name == name {
+			return seg
+		}
+	}
+	return nil
 }
 
 func (s *Status) Render() {
@@ -60,119 +146,190 @@ func (s *Status) Render() {
 	draw2dkit.Rectangle(gc, 0, 0, s.Texture.Width, s.Texture.Height)
 	gc.Fill()
 
-	text_height := FontPadding
-	font.DrawString(data, font.Width, text_height, s.Time, color.Black)
-
-	thermalText := fmt.Sprintf("%dC", s.Stats.ThermalValue)
-	fanText := fmt.Sprintf("%d RPM L%d", s.Stats.FanValue, s.Stats.FanLevel)
-	memoryText := fmt.Sprintf("%.2f%% RAM", s.Stats.MemoryValue)
-	cpuText := fmt.Sprintf("%.2f%% CPU", s.Stats.CpuValue)
+	groups := map[Align][]rendered{}
+	for _, seg := range s.Segments {
+		if seg.Hidden {
+			continue
+		}
+		warn := seg.Warn != nil && seg.Warn(s)
+		groups[seg.Align] = append(groups[seg.Align], rendered{seg, seg.Text(s), warn})
+	}
 
-	buf := strings.Join([]string{memoryText, fanText, thermalText, cpuText, s.Network, s.Battery}, "  |  ")
-	right := int(s.Texture.Width) - ((len(buf) * font.Width) + font.Width)
-	font.DrawString(data, right, text_height, buf, color.Black)
+	width := int(s.Texture.Width)
+	s.drawGroup(gc, data, groups[AlignLeft], font.Width)
+	s.drawGroup(gc, data, groups[AlignCenter], (width-groupWidth(groups[AlignCenter]))/2)
+	s.drawGroup(gc, data, groups[AlignRight], width-(groupWidth(groups[AlignRight])+font.Width))
 
 	s.Texture.Write(&data.Pix)
 }
 
 func (s *Status) Run() {
+	defer close(s.stopped)
+
+	s.Lock()
 	s.UpdateTime()
 	s.UpdateNetwork()
-	s.UpdateBattery()
-	s.Redraw <- true
+	s.Unlock()
+	if !s.redraw() {
+		return
+	}
 
 	five := time.NewTicker(time.Second * 5)
-	ten := time.NewTicker(time.Second * 10)
+	defer five.Stop()
 	for {
 		select {
 		case <-five.C:
+			s.Lock()
 			s.UpdateTime()
 			s.UpdateNetwork()
+			s.Unlock()
 			break
-		case <-ten.C:
-			s.UpdateBattery()
-			break
+		case <-s.quit:
+			return
 		}
-		s.Redraw <- true
+		if !s.redraw() {
+			return
+		}
+	}
+}
+
+// redraw asks for the status to be rendered, false once stopped.
+func (s *Status) redraw() bool {
+	select {
+	case s.Redraw <- true:
+		return true
+	case <-s.quit:
+		return false
 	}
 }
 
+// Stop stops Run and waits for it to return, after which the traffic totals
+// are no longer written to and can be saved.
+func (s *Status) Stop() {
+	close(s.quit)
+	<-s.stopped
+}
+
 func (s *Status) UpdateTime() {
 	//s.Time = time.Now().Format("15:04:05 02.01.2006")
 	s.Time = time.Now().Format("15:04 02.01.2006")
 }
 
-var NetworkNamesMap map[string]string = map[string]string{
-	"enp0s25": "lan",
-	"wlp3s0":  "wifi",
-}
-
+// UpdateNetwork samples the selected interfaces and sums them up by the name
+// they are shown as. Interfaces without any traffic yet are left out.
 func (s *Status) UpdateNetwork() {
 	stats, _ := psutil_net.IOCounters(true)
-	networks := []string{}
-
-	isAvailable := map[string]bool{}
-	for id, _ := range s.NetworkMap {
-		isAvailable[id] = false
-	}
+	now := time.Now()
 
+	available := map[string]bool{}
+	shown := map[string]*Net{}
+	names := []string{}
 	for _, v := range stats {
-		if v.Name == "lo" || v.BytesRecv == 0 {
+		if v.BytesRecv == 0 || !s.NetworkConfig.Selects(v.Name) {
 			continue
 		}
-		if alias, ok := NetworkNamesMap[v.Name]; ok {
-			v.Name = alias
-		}
+		available[v.Name] = true
 
-		isAvailable[v.Name] = true
-
-		var net *Net
-		var ok bool
-
-		if net, ok = s.NetworkMap[v.Name]; ok {
-			now := time.Now()
-			timeDiff := now.Sub(net.Time)
-			net.Time = now
-
-			recvDiff := math.Abs(float64(net.LastBytesRecv) - float64(v.BytesRecv))
-			sentDiff := math.Abs(float64(net.LastBytesSent) - float64(v.BytesSent))
-			net.LastBytesRecv = v.BytesRecv
-			net.LastBytesSent = v.BytesSent
-
-			net.RateRecv = recvDiff / timeDiff.Seconds()
-			net.RateSent = sentDiff / timeDiff.Seconds()
-		} else {
-			net = &Net{
-				Time:          time.Now(),
-				Name:          v.Name,
-				LastBytesRecv: v.BytesRecv,
-				LastBytesSent: v.BytesSent,
-				RateRecv:      0,
-				RateSent:      0,
+		iface, ok := s.interfaces[v.Name]
+		if !ok {
+			iface = &Net{
+				Name: v.Name,
+				recv: widgets.NewCounter(NetworkMaxGap),
+				sent: widgets.NewCounter(NetworkMaxGap),
 			}
-			s.NetworkMap[v.Name] = net
+			s.interfaces[v.Name] = iface
 		}
+		iface.Time = now
+		iface.LastBytesRecv = v.BytesRecv
+		iface.LastBytesSent = v.BytesSent
+		iface.RateRecv = iface.recv.Update(v.BytesRecv, now)
+		iface.RateSent = iface.sent.Update(v.BytesSent, now)
+		s.Accounting.Add(v.Name, v.BytesRecv, v.BytesSent, now)
 
-		buf := fmt.Sprintf("%.1f-%s-%.1f", net.RateRecv/1024, v.Name, net.RateSent/1024)
-		networks = append(networks, buf)
+		name := s.NetworkConfig.Name(v.Name)
+		net, ok := shown[name]
+		if !ok {
+			net = &Net{Name: name, Time: now, RateRecv: math.NaN(), RateSent: math.NaN()}
+			shown[name] = net
+			names = append(names, name)
+		}
+		net.add(iface)
 	}
 
-	for id, state := range isAvailable {
-		if state == false {
-			delete(s.NetworkMap, id)
+	for name := range s.interfaces {
+		if !available[name] {
+			delete(s.interfaces, name)
+			s.Accounting.Forget(name)
 		}
 	}
 
-	s.Network = strings.Join(networks, " | ")
-}
+	s.updateQuotas(now)
+	today, month := map[string]widgets.Traffic{}, map[string]widgets.Traffic{}
+	for _, name := range names {
+		today[name] = s.traffic(name, "day", now)
+		month[name] = s.traffic(name, "month", now)
+	}
+	s.today, s.month = today, month
+	if err := s.Accounting.SaveEvery(AccountingInterval); err != nil {
+		log.Println("failed to save traffic totals:", err)
+	}
 
-func (s *Status) UpdateBattery() {
-	b, err := ReadBattery("BAT0")
-	if err == nil {
-		if b.Status == "Idle" {
-			s.Battery = fmt.Sprintf("idle %.0f%%", b.Percent)
-		} else {
-			s.Battery = fmt.Sprintf("%s %sh %.0fmA %.0f%%", strings.ToLower(b.Status), b.Remaining, b.Amps, b.Percent)
+	s.NetworkConfig.Sort(names)
+	networks := []*Net{}
+	rates := []*widgets.Series{}
+	summary := []string{}
+	for _, name := range names {
+		net := shown[name]
+		networks = append(networks, net)
+		rates = append(rates, s.rate(name, addKnown(net.RateRecv, net.RateSent)))
+		summary = append(summary, fmt.Sprintf("%s-%s-%s", kilo(net.RateRecv), name, kilo(net.RateSent)))
+	}
+	for name := range s.rates {
+		if _, ok := shown[name]; !ok {
+			delete(s.rates, name)
 		}
 	}
+
+	s.Networks = networks
+	s.Rates = rates
+	s.NetworkMap = shown
+	s.Network = strings.Join(summary, " | ")
+}
+
+// rate pushes the rate of the network name, in KiB/s, to its series.
+func (s *Status) rate(name string, rate float64) *widgets.Series {
+	series, ok := s.rates[name]
+	if !ok {
+		series = widgets.NewSeries(name, "KiB/s", 60)
+		s.rates[name] = series
+	}
+	series.Push(rate / 1024)
+	return series
+}
+
+// add sums iface into the group net. The group rate is unknown only while
+// the rates of all its interfaces are.
+func (net *Net) add(iface *Net) {
+	net.LastBytesRecv += iface.LastBytesRecv
+	net.LastBytesSent += iface.LastBytesSent
+	net.RateRecv = addKnown(net.RateRecv, iface.RateRecv)
+	net.RateSent = addKnown(net.RateSent, iface.RateSent)
+}
+
+func addKnown(a, b float64) float64 {
+	if math.IsNaN(a) {
+		return b
+	}
+	if math.IsNaN(b) {
+		return a
+	}
+	return a + b
+}
+
+// kilo formats a byte rate in KiB/s, "?" while unknown.
+func kilo(rate float64) string {
+	if math.IsNaN(rate) {
+		return "?"
+	}
+	return fmt.Sprintf("%.1f", rate/1024)
 }
@@ -0,0 +1,281 @@
+package status
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/maurodelazeri/harvey-gl/config"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+	"github.com/maurodelazeri/harvey-gl/widgets/graph"
+
+	font "github.com/maurodelazeri/harvey-gl/font/terminus"
+)
+
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+var SparkWidth int = 30
+var SparkHeight int = 10
+var BarWidth int = 30
+
+var DefaultSeparator string = "  |  "
+
+// WarnColor is what segments are drawn in while their Warn is true.
+var WarnColor color.RGBA = color.RGBA{0xcc, 0x33, 0x33, 0xff}
+
+// Segment is one part of the status bar: text, optionally followed by a
+// sparkline of a series or a progress bar. Segments are laid out in order
+// within their alignment group.
+type Segment struct {
+	Name       string
+	Align      Align
+	Foreground color.RGBA
+	Background color.RGBA
+	MinWidth   int
+	Separator  string
+	Hidden     bool
+
+	// Format is the text/template source of Text, executed against Data.
+	Format string
+	Text   func(s *Status) string
+	Spark  func(s *Status) *widgets.Series
+	Bar    func(s *Status) float64
+
+	// Warn draws the segment in WarnColor while it returns true.
+	Warn func(s *Status) bool
+}
+
+// Segments maps the segment types of the config to their constructors. The
+// constructors set the default Format, NewSegment turns it into Text.
+var Segments map[string]func() *Segment = map[string]func() *Segment{
+	"clock": func() *Segment {
+		return &Segment{Format: "{{.Time}}"}
+	},
+	"memory": func() *Segment {
+		return &Segment{
+			Format: `{{printf "%.2f" .Memory}}% RAM`,
+			Bar:    func(s *Status) float64 { return s.Stats.Memory.Value / 100 },
+		}
+	},
+	"fan": func() *Segment {
+		return &Segment{Format: `{{printf "%.0f" .Fan}} RPM{{if .Stats.FanLevel.Values}}` +
+			` {{if known .FanLevel}}L{{printf "%.0f" .FanLevel}}{{else}}auto{{end}}{{end}}`}
+	},
+	"fans": func() *Segment {
+		return &Segment{
+			Format: `{{range $i, $f := .Stats.Fans}}{{if $i}} {{end}}{{$f.Name}} {{printf "%.0f" $f.RPM.Value}}` +
+				`{{if $f.PWM}} {{printf "%.0f" $f.Duty.Value}}%{{end}}{{end}}`,
+		}
+	},
+	"fancontrol": func() *Segment {
+		return &Segment{Format: `{{with .Stats.FanControl}}{{.}}{{end}}`}
+	},
+	"thermal": func() *Segment {
+		return &Segment{
+			Format: `{{printf "%.0f" .Thermal}}C`,
+			Spark:  func(s *Status) *widgets.Series { return s.Stats.Thermal },
+		}
+	},
+	"cpu": func() *Segment {
+		return &Segment{
+			Format: `{{printf "%.2f" .Cpu}}% CPU`,
+			Spark:  func(s *Status) *widgets.Series { return s.Stats.Cpu },
+		}
+	},
+	"network": func() *Segment {
+		return &Segment{Format: "{{.Network}}"}
+	},
+	"traffic": func() *Segment {
+		return &Segment{
+			Format: `{{range $i, $q := .Quotas}}{{if $i}} {{end}}{{$q.Network}} {{bytes $q.Used}}/{{bytes $q.Limit}}` +
+				`{{if $q.Warning}}!{{end}}{{end}}`,
+			Bar:  func(s *Status) float64 { return s.quotaRatio() },
+			Warn: func(s *Status) bool { return s.quotaWarning() },
+		}
+	},
+	"load": func() *Segment {
+		return &Segment{
+			Format: `{{printf "%.2f %.2f %.2f" .Load1 .Load5 .Load15}}`,
+			Spark:  func(s *Status) *widgets.Series { return s.Stats.System.Load1 },
+		}
+	},
+	"uptime": func() *Segment {
+		return &Segment{Format: `up {{duration .Uptime}}`}
+	},
+	"pressure": func() *Segment {
+		return &Segment{Format: `{{with .Stats.Stalling 10.0}}{{.Resource}} stall {{printf "%.0f" .Some.Avg10}}%{{end}}`}
+	},
+	"power": func() *Segment {
+		return &Segment{
+			Format: `{{if .AC}}AC{{else}}BAT{{end}}`,
+			Spark:  func(s *Status) *widgets.Series { return s.Stats.AC },
+		}
+	},
+	"battery": func() *Segment {
+		return &Segment{
+			Format: `{{with .Battery}}{{if not .BatteryID}}{{else if eq .Status "Idle"}}idle {{printf "%.0f" .Percent}}%` +
+				`{{else}}{{lower .Status}} {{with $.Estimate}}{{if .Valid}}{{duration .Seconds}} ({{duration .Low}}-{{duration .High}}) {{end}}{{end}}` +
+				`{{printf "%.1f" .Power}}W {{printf "%.0f" .Percent}}%{{end}}{{end}}`,
+			Bar: func(s *Status) float64 { return s.Stats.Battery.Value / 100 },
+		}
+	},
+}
+
+// NewSegment builds a segment from its config.
+func NewSegment(c config.Segment) (*Segment, error) {
+	var seg *Segment
+	if c.Type == "custom" {
+		seg = &Segment{}
+		if c.Format == "" {
+			text := c.Text
+			seg.Text = func(s *Status) string { return text }
+		}
+	} else if constructor, ok := Segments[c.Type]; ok {
+		seg = constructor()
+	} else {
+		return nil, fmt.Errorf("unknown segment type %q", c.Type)
+	}
+
+	seg.Name = c.Name
+	if seg.Name == "" {
+		seg.Name = c.Type
+	}
+
+	if c.Format != "" {
+		seg.Format = c.Format
+	}
+	if seg.Format != "" {
+		text, err := textTemplate(seg.Name, seg.Format)
+		if err != nil {
+			return nil, fmt.Errorf("segment %s: %v", seg.Name, err)
+		}
+		seg.Text = text
+	}
+
+	switch c.Align {
+	case "left":
+		seg.Align = AlignLeft
+	case "center":
+		seg.Align = AlignCenter
+	case "right", "":
+		seg.Align = AlignRight
+	default:
+		return nil, fmt.Errorf("segment %s: unknown alignment %q", seg.Name, c.Align)
+	}
+
+	var err error
+	if seg.Foreground, err = config.ParseColor(c.Foreground, color.RGBA{0x00, 0x00, 0x00, 0xff}); err != nil {
+		return nil, fmt.Errorf("segment %s: %v", seg.Name, err)
+	}
+	if seg.Background, err = config.ParseColor(c.Background, color.RGBA{}); err != nil {
+		return nil, fmt.Errorf("segment %s: %v", seg.Name, err)
+	}
+
+	seg.MinWidth = c.MinWidth
+	seg.Hidden = c.Hidden
+	seg.Separator = DefaultSeparator
+	if c.Separator != nil {
+		seg.Separator = *c.Separator
+	}
+
+	switch c.Inline {
+	case "":
+	case "none":
+		seg.Spark, seg.Bar = nil, nil
+	case "spark":
+		if seg.Spark == nil {
+			return nil, fmt.Errorf("segment %s has no series for a sparkline", seg.Name)
+		}
+		seg.Bar = nil
+	case "bar":
+		if seg.Bar == nil {
+			return nil, fmt.Errorf("segment %s has no value for a progress bar", seg.Name)
+		}
+		seg.Spark = nil
+	default:
+		return nil, fmt.Errorf("segment %s: unknown inline graphic %q", seg.Name, c.Inline)
+	}
+
+	return seg, nil
+}
+
+// rendered is a segment with its text resolved for a single frame.
+type rendered struct {
+	*Segment
+	text string
+	warn bool
+}
+
+func (r rendered) foreground() color.RGBA {
+	if r.warn {
+		return WarnColor
+	}
+	return r.Foreground
+}
+
+func (r rendered) width() int {
+	chars := len(r.text)
+	if chars < r.MinWidth {
+		chars = r.MinWidth
+	}
+	width := chars * font.Width
+	if r.Spark != nil {
+		width += font.Width + SparkWidth
+	}
+	if r.Bar != nil {
+		width += font.Width + BarWidth
+	}
+	return width
+}
+
+func groupWidth(group []rendered) int {
+	width := 0
+	for i, r := range group {
+		if i > 0 {
+			width += len(r.Separator) * font.Width
+		}
+		width += r.width()
+	}
+	return width
+}
+
+func (s *Status) drawGroup(gc *draw2dimg.GraphicContext, data *image.RGBA, group []rendered, x int) {
+	y := FontPadding
+	inlineTop := float64(int(s.Texture.Height)-SparkHeight) / 2
+
+	for i, r := range group {
+		if i > 0 {
+			x, _ = font.DrawString(data, x, y, r.Separator, r.Foreground)
+		}
+
+		width := r.width()
+		if r.Background.A != 0 {
+			gc.SetFillColor(r.Background)
+			draw2dkit.Rectangle(gc, float64(x), 0, float64(x+width), s.Texture.Height)
+			gc.Fill()
+		}
+
+		end := x + width
+		fg := r.foreground()
+		x, _ = font.DrawString(data, x, y, r.text, fg)
+		if r.Spark != nil {
+			x += font.Width
+			graph.Sparkline(gc, r.Spark(s), float64(x), inlineTop, float64(SparkWidth), float64(SparkHeight), fg)
+			x += SparkWidth
+		}
+		if r.Bar != nil {
+			x += font.Width
+			bg := color.RGBA{fg.R, fg.G, fg.B, 0x44}
+			graph.ProgressBar(gc, r.Bar(s), float64(x), inlineTop, float64(BarWidth), float64(SparkHeight), fg, bg)
+		}
+		x = end
+	}
+}
@@ -0,0 +1,158 @@
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/maurodelazeri/harvey-gl/widgets"
+)
+
+// Data is what segment format templates are executed against, e.g.
+//
+//	{{.Battery.Percent | printf "%.0f"}}% {{if .Battery.Charging}}+{{end}}
+type Data struct {
+	Now      time.Time
+	Time     string
+	Memory   float64
+	Cpu      float64
+	Thermal  float64
+	Fan      float64
+	Network  string
+	Networks []*Net
+
+	// FanLevel is the latest thinkpad_acpi level, NaN while the firmware
+	// controls the fan, see known.
+	FanLevel float64
+
+	// Load1, Load5 and Load15 are the load averages, Uptime is in seconds.
+	Load1     float64
+	Load5     float64
+	Load15    float64
+	Processes float64
+	Uptime    float64
+	Boot      time.Time
+
+	// Today and Month are the traffic totals of the networks by name, Quotas
+	// their limits.
+	Today  map[string]widgets.Traffic
+	Month  map[string]widgets.Traffic
+	Quotas []*Quota
+
+	// Battery is the combined pack of Batteries, it is empty when there is
+	// no battery.
+	Battery   widgets.BatteryStatus
+	Batteries []widgets.BatteryStatus
+
+	// AC is true while the machine runs on external power.
+	AC bool
+
+	// Estimate is the smoothed time to empty or full of Battery.
+	Estimate widgets.Estimate
+
+	Stats *widgets.Stats
+}
+
+func (s *Status) Data() *Data {
+	d := &Data{
+		Now:      time.Now(),
+		Time:     s.Time,
+		Memory:   s.Stats.Memory.Value,
+		Cpu:      s.Stats.Cpu.Value,
+		Thermal:  s.Stats.Thermal.Value,
+		Fan:      s.Stats.Fan.Value,
+		FanLevel: s.Stats.FanLevel.Latest(),
+		Network:  s.Network,
+		Networks: s.Networks,
+
+		Load1:     s.Stats.System.Load1.Value,
+		Load5:     s.Stats.System.Load5.Value,
+		Load15:    s.Stats.System.Load15.Value,
+		Processes: s.Stats.System.Processes.Value,
+		Uptime:    s.Stats.System.Uptime.Seconds(),
+		Boot:      s.Stats.System.Boot,
+
+		Today:    s.today,
+		Month:    s.month,
+		Quotas:   s.Quotas,
+		AC:       s.Stats.Online,
+		Estimate: s.Stats.Estimate,
+		Stats:    s.Stats,
+	}
+	if s.Stats.Pack != nil {
+		d.Battery = *s.Stats.Pack
+		d.Batteries = s.Stats.Batteries
+	}
+	return d
+}
+
+// Funcs are the helpers available to segment templates on top of the
+// text/template builtins.
+var Funcs template.FuncMap = template.FuncMap{
+	"bytes":      HumanBytes,
+	"duration":   HumanDuration,
+	"round":      round,
+	"fahrenheit": func(c float64) float64 { return (c * 9 / 5) + 32 },
+	"mul":        func(a, b float64) float64 { return a * b },
+	"div":        func(a, b float64) float64 { return a / b },
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"known":      func(value float64) bool { return !math.IsNaN(value) },
+	"choose": func(cond bool, a, b interface{}) interface{} {
+		if cond {
+			return a
+		}
+		return b
+	},
+}
+
+// HumanBytes formats a byte count with binary prefixes, "1.5 MiB".
+func HumanBytes(value float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := 0
+	for math.Abs(value) >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", value, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
+
+// HumanDuration formats seconds as "1h20m", "5m" or "40s".
+func HumanDuration(seconds float64) string {
+	d := time.Duration(seconds) * time.Second
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
+
+func round(value float64, digits int) float64 {
+	p := math.Pow(10, float64(digits))
+	return math.Round(value*p) / p
+}
+
+// textTemplate returns a Segment.Text func executing the given format.
+func textTemplate(name, format string) (func(s *Status) string, error) {
+	t, err := template.New(name).Funcs(Funcs).Parse(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(s *Status) string {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, s.Data()); err != nil {
+			return "ERR " + name
+		}
+		return buf.String()
+	}, nil
+}
@@ -0,0 +1,97 @@
+package status
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/maurodelazeri/harvey-gl/config"
+	"github.com/maurodelazeri/harvey-gl/widgets"
+)
+
+// Quota is the traffic of a network in the current day or month against its
+// configured limit.
+type Quota struct {
+	Network string
+	Period  string
+	Used    float64
+	Limit   float64
+	Ratio   float64
+	Warn    float64
+
+	// Warning is set once the used share reaches Warn percent.
+	Warning bool
+}
+
+func newQuota(c config.Quota) (*Quota, error) {
+	if c.Period != "day" && c.Period != "month" {
+		return nil, fmt.Errorf("quota %s: unknown period %q", c.Network, c.Period)
+	}
+	limit, err := config.ParseBytes(c.Limit)
+	if err != nil || limit == 0 {
+		return nil, fmt.Errorf("quota %s: invalid limit %q", c.Network, c.Limit)
+	}
+
+	q := &Quota{Network: c.Network, Period: c.Period, Limit: limit, Warn: c.Warn}
+	if q.Warn == 0 {
+		q.Warn = 80
+	}
+	return q, nil
+}
+
+// AccountingInterval is how often the traffic totals are written to disk.
+var AccountingInterval time.Duration = time.Minute
+
+// traffic sums the traffic of the interfaces shown as name in the day or
+// month of now.
+func (s *Status) traffic(name, period string, now time.Time) widgets.Traffic {
+	total := widgets.Traffic{}
+	for iface := range s.Accounting.Interfaces {
+		if s.NetworkConfig.Name(iface) != name {
+			continue
+		}
+		t := s.Accounting.Day(iface, now)
+		if period == "month" {
+			t = s.Accounting.Month(iface, now)
+		}
+		total.Recv += t.Recv
+		total.Sent += t.Sent
+	}
+	return total
+}
+
+// updateQuotas recomputes the quotas, logging once per period when one
+// crosses its threshold. The traffic segment shows the warning meanwhile.
+func (s *Status) updateQuotas(now time.Time) {
+	for _, q := range s.Quotas {
+		q.Used = float64(s.traffic(q.Network, q.Period, now).Total())
+		q.Ratio = q.Used / q.Limit
+
+		warning := q.Ratio*100 >= q.Warn
+		if warning && !q.Warning {
+			log.Printf("%s used %s of its %s %s quota", q.Network, HumanBytes(q.Used), q.Period, HumanBytes(q.Limit))
+		}
+		q.Warning = warning
+	}
+}
+
+// quotaRatio is the highest used share of any quota.
+func (s *Status) quotaRatio() float64 {
+	ratio := 0.0
+	for _, q := range s.Quotas {
+		if q.Ratio > ratio {
+			ratio = q.Ratio
+		}
+	}
+	return ratio
+}
+
+// quotaWarning is true while any quota is past its warning threshold.
+func (s *Status) quotaWarning() bool {
+	for _, q := range s.Quotas {
+		if q.Warning {
+			return true
+		}
+	}
+	return false
+}
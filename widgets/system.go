@@ -0,0 +1,120 @@
+package widgets
+
+import (
+	"io/ioutil"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// System is the classic overview: load averages, process counts, context
+// switches and interrupts, uptime and boot time.
+type System struct {
+	// Load1, Load5 and Load15 are the load averages, divided by the number
+	// of cores when PerCore is set.
+	Load1   *Series
+	Load5   *Series
+	Load15  *Series
+	PerCore bool
+
+	// Running and Blocked count the tasks runnable and waiting for I/O,
+	// Processes all processes.
+	Running   *Series
+	Blocked   *Series
+	Processes *Series
+
+	// ContextSwitches and Interrupts are per second.
+	ContextSwitches *Series
+	Interrupts      *Series
+
+	Uptime time.Duration
+	Boot   time.Time
+
+	ctxt *Counter
+	intr *Counter
+}
+
+func NewSystem() *System {
+	return &System{
+		Load1:           NewSeries("load1", "", 60),
+		Load5:           NewSeries("load5", "", 60),
+		Load15:          NewSeries("load15", "", 60),
+		Running:         NewSeries("running", "", 60),
+		Blocked:         NewSeries("blocked", "", 60),
+		Processes:       NewSeries("processes", "", 60),
+		ContextSwitches: NewSeries("context switches", "/s", 60),
+		Interrupts:      NewSeries("interrupts", "/s", 60),
+		ctxt:            NewCounter(time.Second * 30),
+		intr:            NewCounter(time.Second * 30),
+	}
+}
+
+func (s *System) update(now time.Time) {
+	// 0.20 0.18 0.12 1/80 11206
+	if buf, err := ioutil.ReadFile("/proc/loadavg"); err == nil {
+		fields := strings.Fields(string(buf))
+		if len(fields) >= 3 {
+			cores := 1.0
+			if s.PerCore {
+				cores = float64(runtime.NumCPU())
+			}
+			for i, series := range []*Series{s.Load1, s.Load5, s.Load15} {
+				if load, err := strconv.ParseFloat(fields[i], 64); err == nil {
+					series.PushAt(load/cores, now)
+				}
+			}
+		}
+	}
+
+	if buf, err := ioutil.ReadFile("/proc/stat"); err == nil {
+		for _, line := range strings.Split(string(buf), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			value, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			switch fields[0] {
+			case "ctxt":
+				s.ContextSwitches.PushAt(s.ctxt.Update(value, now), now)
+			case "intr":
+				// The total, followed by the count of every interrupt.
+				s.Interrupts.PushAt(s.intr.Update(value, now), now)
+			case "procs_running":
+				s.Running.PushAt(float64(value), now)
+			case "procs_blocked":
+				s.Blocked.PushAt(float64(value), now)
+			case "btime":
+				s.Boot = time.Unix(int64(value), 0)
+			}
+		}
+	}
+
+	if buf, err := ioutil.ReadFile("/proc/uptime"); err == nil {
+		fields := strings.Fields(string(buf))
+		if len(fields) > 0 {
+			if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil {
+				s.Uptime = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	if dirs, err := ioutil.ReadDir("/proc"); err == nil {
+		processes := 0
+		for _, dir := range dirs {
+			if dir.IsDir() && strings.IndexFunc(dir.Name(), func(r rune) bool { return !unicode.IsDigit(r) }) == -1 {
+				processes++
+			}
+		}
+		s.Processes.PushAt(float64(processes), now)
+	}
+}
+
+func (s *Stats) UpdateSystem() {
+	s.System.update(time.Now())
+}